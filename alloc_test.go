@@ -0,0 +1,77 @@
+package BrodalOkasakiHeap
+
+
+import (
+    "testing"
+    "math/rand"
+)
+
+
+// Pop() and Merge() only relink nodes that already exist, so they shouldn't allocate at all.
+
+func Test_pop_allocs(t *testing.T) {
+    const SIZE = 100000
+    rand.Seed(1)
+
+    heap := NewBOHeap()
+    insert_mult(heap, shuffle(interval(0, SIZE)))
+
+    allocs := testing.AllocsPerRun(SIZE/2, func() {
+        heap.Pop()
+    })
+    if allocs != 0 {t.Errorf("Pop() allocated %.2f times per run", allocs)}
+}
+
+
+func Test_merge_allocs(t *testing.T) {
+    const SIZE = 1000
+    rand.Seed(1)
+
+    // AllocsPerRun makes one extra warm-up run.
+    heaps := make([]*BOHeap, 0, 102)
+    for i:=0; i<102; i++ {
+        heap := NewBOHeap()
+        insert_mult(heap, shuffle(interval(i*SIZE, (i+1)*SIZE)))
+        heaps = append(heaps, heap)
+    }
+
+    next := 1
+    allocs := testing.AllocsPerRun(100, func() {
+        heaps[0].Merge(heaps[next])
+        heaps[0].Pop()
+        next += 1
+    })
+    if allocs != 0 {t.Errorf("Merge() and Pop() allocated %.2f times per run", allocs)}
+}
+
+
+func BenchmarkPopMergeAllocs(b *testing.B) {
+    // Every run merges a heap of SIZE keys and pops them all again, so the heap stays small. The heaps to merge are
+    // built in batches outside of the timer.
+    const (
+        SIZE = 64
+        BATCH = 256
+    )
+    rand.Seed(1)
+
+    heap := NewBOHeap()
+    others := make([]*BOHeap, BATCH)
+
+    b.ReportAllocs()
+    b.ResetTimer()
+    for i:=0; i<b.N; i++ {
+        if i%BATCH == 0 {
+            b.StopTimer()
+            for j := range others {
+                others[j] = NewBOHeap()
+                insert_mult(others[j], shuffle(interval(0, SIZE)))
+            }
+            b.StartTimer()
+        }
+
+        heap.Merge(others[i%BATCH])
+        for j:=0; j<SIZE; j++ {
+            heap.Pop()
+        }
+    }
+}
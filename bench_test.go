@@ -0,0 +1,201 @@
+package BrodalOkasakiHeap_test
+
+
+import (
+    "fmt"
+    "math/rand"
+    "testing"
+
+    "github.com/cngkaygusuz/BrodalOkasakiHeap"
+)
+
+
+/*
+Benchmarks of BOHeap against the usual suspects. Every benchmark runs for each implementation and each size, e.g.
+
+    go test -run NONE -bench 'Pop/BOHeap' -benchmem
+
+Keys come from a fixed seed, so every implementation sees exactly the same input.
+ */
+
+
+var benchQueues = []struct {
+    name		string
+    newQueue	func() BrodalOkasakiHeap.PriorityQueue
+}{
+    {"BOHeap", func() BrodalOkasakiHeap.PriorityQueue { return BrodalOkasakiHeap.NewBOHeap() }},
+    {"CompactBOHeap", func() BrodalOkasakiHeap.PriorityQueue { return BrodalOkasakiHeap.NewCompactBOHeap() }},
+    {"SkewBinomialHeap", func() BrodalOkasakiHeap.PriorityQueue { return BrodalOkasakiHeap.NewSkewBinomialHeap() }},
+    {"container-heap", newStdQueue},
+    {"BinomialHeap", func() BrodalOkasakiHeap.PriorityQueue { return BrodalOkasakiHeap.NewBinomialHeap() }},
+    {"PairingHeap", func() BrodalOkasakiHeap.PriorityQueue { return BrodalOkasakiHeap.NewPairingHeap() }},
+    {"FibonacciHeap", func() BrodalOkasakiHeap.PriorityQueue { return BrodalOkasakiHeap.NewFibonacciHeap() }},
+    {"StrictFibonacciHeap", func() BrodalOkasakiHeap.PriorityQueue { return BrodalOkasakiHeap.NewStrictFibonacciHeap() }},
+}
+
+var benchSizes = []int{100, 10000, 1000000}
+
+const benchSeed = 42
+
+
+func benchKeys(n int) []int {
+    rng := rand.New(rand.NewSource(benchSeed))
+    keys := make([]int, n)
+    for i := range keys {
+        keys[i] = rng.Int()
+    }
+    return keys
+}
+
+
+/*
+Run "bench" for every implementation and size.
+ */
+func runBench(b *testing.B, bench func(b *testing.B, newQueue func() BrodalOkasakiHeap.PriorityQueue, n int)) {
+    for _, bq := range benchQueues {
+        for _, n := range benchSizes {
+            b.Run(fmt.Sprintf("%s/n=%d", bq.name, n), func(b *testing.B) {
+                b.ReportAllocs()
+                bench(b, bq.newQueue, n)
+            })
+        }
+    }
+}
+
+
+/*
+Insert into a queue, starting over with an empty one every n inserts.
+ */
+func BenchmarkInsert(b *testing.B) {
+    runBench(b, func(b *testing.B, newQueue func() BrodalOkasakiHeap.PriorityQueue, n int) {
+        keys := benchKeys(n)
+        pq := newQueue()
+
+        b.ResetTimer()
+        for i := 0; i < b.N; i++ {
+            if pq.Size() == n {
+                pq = newQueue()
+            }
+            pq.Insert(keys[i%n])
+        }
+    })
+}
+
+
+/*
+Pop from a queue of n keys, refilling it (untimed) whenever it runs dry.
+ */
+func BenchmarkPop(b *testing.B) {
+    runBench(b, func(b *testing.B, newQueue func() BrodalOkasakiHeap.PriorityQueue, n int) {
+        keys := benchKeys(n)
+        pq := newQueue()
+
+        b.ResetTimer()
+        for i := 0; i < b.N; i++ {
+            if pq.Size() == 0 {
+                b.StopTimer()
+                for _, key := range keys {
+                    pq.Insert(key)
+                }
+                b.StartTimer()
+            }
+            pq.Pop()
+        }
+    })
+}
+
+
+func BenchmarkPeek(b *testing.B) {
+    runBench(b, func(b *testing.B, newQueue func() BrodalOkasakiHeap.PriorityQueue, n int) {
+        pq := newQueue()
+        for _, key := range benchKeys(n) {
+            pq.Insert(key)
+        }
+
+        b.ResetTimer()
+        for i := 0; i < b.N; i++ {
+            pq.Peek()
+        }
+    })
+}
+
+
+/*
+Merge single-key queues into a queue that grows up to n keys, which is how merging shows up in most workloads. Building
+a fresh pair of large queues for every merge would dwarf the merge itself for the O(1) implementations.
+ */
+func BenchmarkMerge(b *testing.B) {
+    runBench(b, func(b *testing.B, newQueue func() BrodalOkasakiHeap.PriorityQueue, n int) {
+        keys := benchKeys(n)
+        pq := newQueue()
+
+        b.ResetTimer()
+        for i := 0; i < b.N; i++ {
+            if pq.Size() == n {
+                pq = newQueue()
+            }
+            other := newQueue()
+            other.Insert(keys[i%n])
+            pq.Merge(other)
+        }
+    })
+}
+
+
+/*
+A queue holding around n keys, taking a seeded mix of 40% inserts, 40% pops, 15% peeks and 5% merges of 8 key queues.
+ */
+func BenchmarkMixed(b *testing.B) {
+    const (
+        opInsert = iota
+        opPop
+        opPeek
+        opMerge
+    )
+
+    runBench(b, func(b *testing.B, newQueue func() BrodalOkasakiHeap.PriorityQueue, n int) {
+        keys := benchKeys(n)
+        rng := rand.New(rand.NewSource(benchSeed))
+        ops := make([]int, 4096)
+        for i := range ops {
+            switch r := rng.Intn(100); {
+            case r < 40:
+                ops[i] = opInsert
+            case r < 80:
+                ops[i] = opPop
+            case r < 95:
+                ops[i] = opPeek
+            default:
+                ops[i] = opMerge
+            }
+        }
+
+        pq := newQueue()
+        for _, key := range keys {
+            pq.Insert(key)
+        }
+
+        b.ResetTimer()
+        for i := 0; i < b.N; i++ {
+            key := keys[i%n]
+            switch ops[i%len(ops)] {
+            case opInsert:
+                pq.Insert(key)
+            case opPop:
+                if pq.Size() > 0 {
+                    pq.Pop()
+                }
+            case opPeek:
+                if pq.Size() > 0 {
+                    pq.Peek()
+                }
+            case opMerge:
+                other := newQueue()
+                for j := 0; j < 8; j++ {
+                    other.Insert(key ^ j)
+                }
+                pq.Merge(other)
+            }
+        }
+    })
+}
@@ -0,0 +1,58 @@
+package BrodalOkasakiHeap_test
+
+
+import (
+    "container/heap"
+
+    "github.com/cngkaygusuz/BrodalOkasakiHeap"
+)
+
+
+/*
+Textbook priority queues BOHeap is benchmarked against. They are kept as simple as they come, since the point is to
+see where the constants of BOHeap stand, not to win a race.
+ */
+
+
+// ====== container/heap ======
+
+
+type intHeap []int
+
+func (ih intHeap) Len() int				{ return len(ih) }
+func (ih intHeap) Less(i, j int) bool	{ return ih[i] < ih[j] }
+func (ih intHeap) Swap(i, j int)		{ ih[i], ih[j] = ih[j], ih[i] }
+func (ih *intHeap) Push(x interface{})	{ *ih = append(*ih, x.(int)) }
+func (ih *intHeap) Pop() interface{} {
+    old := *ih
+    x := old[len(old)-1]
+    *ih = old[:len(old)-1]
+    return x
+}
+
+
+type stdQueue struct {
+    ih	intHeap
+}
+
+func newStdQueue() BrodalOkasakiHeap.PriorityQueue {
+    return &stdQueue{}
+}
+
+func (sq *stdQueue) Insert(value int)	{ heap.Push(&sq.ih, value) }
+func (sq *stdQueue) Peek() int			{ return sq.ih[0] }
+func (sq *stdQueue) Size() int			{ return len(sq.ih) }
+
+func (sq *stdQueue) Pop() int {
+    if len(sq.ih) == 0 {
+        panic("stdQueue: Pop() on an empty queue")
+    }
+    return heap.Pop(&sq.ih).(int)
+}
+
+func (sq *stdQueue) Merge(pq BrodalOkasakiHeap.PriorityQueue) {
+    other := pq.(*stdQueue)
+    sq.ih = append(sq.ih, other.ih...)
+    other.ih = nil
+    heap.Init(&sq.ih)
+}
@@ -0,0 +1,90 @@
+package BrodalOkasakiHeap
+
+
+/*
+An ordinary binomial heap, the structure everything in this package grows out of. Like SkewBinomialHeap, it is a forest
+of trees hanging off a header node, but every rank appears at most once, and a tree of rank r holds exactly 2^r nodes.
+
+    * Insert() adds a tree of rank 0 and links trees of the same rank until the ranks are distinct again, like adding
+      one to a binary number. O(logn) worst case, O(1) amortized.
+    * Peek() scans the trees for the minimum, O(logn).
+    * Merge() and Pop() go through "meldForest", O(logn).
+ */
+type BinomialHeap struct {
+    trees	BONode
+    size	int
+}
+
+
+/*
+Create a new binomial heap.
+ */
+func NewBinomialHeap() *BinomialHeap {
+    return &BinomialHeap{}
+}
+
+
+func (bh *BinomialHeap) Insert(value int) {
+    node := newBONode(value)
+
+    // Trees are rank ordered, so the carry only ever looks at the front of the list.
+    for srnode := bh.trees.children_head; srnode != nil && srnode.rank == node.rank; srnode = bh.trees.children_head {
+        node = simpleLink(MinFirst, srnode, node)
+    }
+    bh.trees.adopt(node)
+
+    bh.size += 1
+}
+
+
+func (bh *BinomialHeap) Pop() int {
+    if bh.size == 0 {
+        panic("BinomialHeap: Pop() on an empty heap")
+    }
+
+    minnode := bh.trees.getMinChild(MinFirst)
+    minnode.rogue()
+    meldForest(&bh.trees, minnode, MinFirst)
+    bh.size -= 1
+
+    return minnode.value
+}
+
+
+func (bh *BinomialHeap) Peek() int {
+    if bh.size == 0 {
+        panic("BinomialHeap: Peek() on an empty heap")
+    }
+    return bh.trees.getMinChild(MinFirst).value
+}
+
+
+func (bh *BinomialHeap) Size() int {
+    return bh.size
+}
+
+
+/*
+"other" must be another *BinomialHeap. Its trees now belong to this heap, so it is left empty.
+ */
+func (bh *BinomialHeap) Merge(pq PriorityQueue) {
+    other, ok := pq.(*BinomialHeap)
+    if !ok {
+        panic("BinomialHeap: can only merge with another *BinomialHeap")
+    }
+    if other == bh {
+        panic("BinomialHeap: can't merge a heap with itself")
+    }
+
+    meldForest(&bh.trees, &other.trees, MinFirst)
+    bh.size += other.size
+    other.size = 0
+}
+
+
+/*
+Same as "SkewBinomialHeap.Validate", but every rank may appear only once.
+ */
+func (bh *BinomialHeap) Validate() error {
+    return validateForest(&bh.trees, bh.size, false)
+}
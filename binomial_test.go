@@ -0,0 +1,79 @@
+package BrodalOkasakiHeap
+
+
+import (
+    "testing"
+    "math/rand"
+)
+
+
+func Test_binomial_insert_pop(t *testing.T) {
+    const SIZE = 1000
+    rand.Seed(1)
+
+    bh := NewBinomialHeap()
+    insert_mult_pq(bh, shuffle(interval(0, SIZE)))
+    if err := bh.Validate(); err != nil {t.Fatal(err)}
+
+    for i:=0; i<SIZE; i++ {
+        if pval := bh.Pop(); pval != i {t.Fatalf("expected %d, got %d", i, pval)}
+        if err := bh.Validate(); err != nil {t.Fatal(err)}
+    }
+}
+
+
+func Test_binomial_ranks(t *testing.T) {
+    // The ranks of the trees are the set bits of the size.
+    bh := NewBinomialHeap()
+    for i:=1; i<=100; i++ {
+        bh.Insert(i)
+
+        bits := 0
+        for tree := bh.trees.children_head; tree != nil; tree = tree.rightsibling {
+            bits |= 1 << uint(tree.rank)
+        }
+        if bits != i {t.Fatalf("size %d with trees of ranks %b", i, bits)}
+    }
+}
+
+
+func Test_binomial_merge_self(t *testing.T) {
+    bh := NewBinomialHeap()
+    insert_mult_pq(bh, interval(0, 10))
+
+    mustPanic(t, func() { bh.Merge(bh) })
+    if bh.Size() != 10 {t.Errorf("expected size 10, got %d", bh.Size())}
+    if err := bh.Validate(); err != nil {t.Fatal(err)}
+    for i:=0; i<10; i++ {
+        if pval := bh.Pop(); pval != i {t.Fatalf("expected %d, got %d", i, pval)}
+    }
+}
+
+
+func Test_binomial_random(t *testing.T) {
+    rng := rand.New(rand.NewSource(1))
+    bh := NewBinomialHeap()
+    ref := NewBOHeap()
+
+    for i:=0; i<5000; i++ {
+        switch op := rng.Intn(10); {
+        case op < 5 || ref.Size() == 0:
+            value := rng.Intn(1000)
+            bh.Insert(value)
+            ref.Insert(value)
+        case op < 9:
+            if bval, rval := bh.Pop(), ref.Pop(); bval != rval {t.Fatalf("op %d: expected %d, got %d", i, rval, bval)}
+        default:
+            other := NewBinomialHeap()
+            for j:=0; j<rng.Intn(20); j++ {
+                value := rng.Intn(1000)
+                other.Insert(value)
+                ref.Insert(value)
+            }
+            bh.Merge(other)
+        }
+
+        if bh.Size() != ref.Size() {t.Fatalf("op %d: expected size %d, got %d", i, ref.Size(), bh.Size())}
+        if err := bh.Validate(); err != nil {t.Fatalf("op %d: %v", i, err)}
+    }
+}
@@ -1,5 +1,8 @@
 package BrodalOkasakiHeap
-import "fmt"
+import (
+    "fmt"
+    "sync/atomic"
+)
 
 
 /*
@@ -9,6 +12,28 @@ implements the priority queue interface using operations defined for "BONode" st
 type BOHeap struct {
     root	*BONode
     size 	int
+    order	Order
+
+    // Rank index of the children of the root, which moves along when another node becomes the root.
+    index	rankIndex
+
+    // Recycled nodes, nil unless the heap is made by NewPooledBOHeap(). Read "nodePool".
+    pool	*nodePool
+
+    // Operation counters, nil unless EnableStats() is called. Read "Stats".
+    stats	*Stats
+    // Set while insert_binomial re-inserts the result of a link, so the next link can be counted as a cascade.
+    relinking	bool
+
+    // Receives every structural change, nil unless SetTracer() is called. Read "Tracer".
+    tracer	Tracer
+
+    // Operation log this heap writes to and its id in that log, set by "Recorder.NewBOHeap".
+    recorder	*Recorder
+    id			int
+
+    // Set for a heap made by NewStableBOHeap(), read "stableSeq".
+    stable		bool
 }
 
 
@@ -35,13 +60,20 @@ of the skew-binomial heaps, which is simply described as follows:
 Much of the logical complexity is hidden in the "skew-linking" procedure. Read "BONode.skewLink".
  */
 func (bq *BOHeap) Insert(value int) {
-    newnode := newBONode(value)
+    newnode := bq.newNode(value)
+    if bq.stable {
+        newnode.seq = atomic.AddUint64(&stableSeq, 1)
+    }
     bq.size += 1
     bq.insert(newnode)
+
+    if bq.recorder != nil {
+        bq.recorder.recordInsert(bq.id, value)
+    }
 }
 
 /*
-Delete and return the minimum valued node.
+Delete and return the minimum valued node, or the maximum valued one for a heap made by NewMaxBOHeap().
 
 Since we do almost everything under Pop(), this function is a bit complicated. I implemented Pop() as follows:
 
@@ -57,22 +89,37 @@ In the original paper, re-inserting the children of a node involves partitioning
 what the authors really meant, and made a slight modification here. Figure out the difference.
  */
 func (bq *BOHeap) Pop() int {
+    if bq.root == nil {
+        panic("BOHeap: Pop() on an empty heap")
+    }
+
     bq.size -= 1
     retval := bq.root.value
+    touched := 0
 
     if bq.root.subqueue_head != nil {
-        bq.merge_subqueue()
+        touched += bq.merge_subqueue()
     }
 
-    minchild := bq.root.getMinChild()
+    minchild := bq.root.getMinChild(bq.order)
 
     if minchild == nil {
         // minchild == nil signifies the heap is empty.
+        bq.freeNode(bq.root)
         bq.root = nil
     } else {
         minchild.rogue()
-        bq.reInsertChildren(minchild)
+        touched += 1 + bq.reInsertChildren(minchild)
         bq.promoteToRoot(minchild)
+        // Only the value and the subqueue of "minchild" live on, in the root.
+        bq.freeNode(minchild)
+    }
+
+    if bq.stats != nil {
+        bq.stats.countPop(touched)
+    }
+    if bq.recorder != nil {
+        bq.recorder.recordPop(bq.id, retval)
     }
 
     return retval
@@ -80,9 +127,13 @@ func (bq *BOHeap) Pop() int {
 
 
 /*
-Return the minimum valued element in the queue.
+Return the minimum valued element in the queue, or the maximum valued one for a max-heap.
  */
 func (bq *BOHeap) Peek() int {
+    if bq.root == nil {
+        panic("BOHeap: Peek() on an empty heap")
+    }
+
     // Minimum is the global root so we have O(1) access time.
     return bq.root.value
 }
@@ -101,14 +152,37 @@ Instead of doing the merge operation immediately, we do it when we need it. This
 Implementation of this operation is:
     * Move the children head to the subqueue head.
     * Insert the root of other queue as if it is a singleton node.
+
+"other" must be another *BOHeap of the same order. Its nodes now belong to this heap, so it is left empty.
  */
-func (bq* BOHeap) Merge(other *BOHeap) {
-    bq.size += other.size
+func (bq* BOHeap) Merge(pq PriorityQueue) {
+    other, ok := pq.(*BOHeap)
+    if !ok {
+        panic("BOHeap: can only merge with another *BOHeap")
+    }
+    if other == bq {
+        panic("BOHeap: can't merge a heap with itself")
+    }
+    if other.order != bq.order {
+        panic("BOHeap: can't merge heaps of different order")
+    }
+
+    if other.root != nil {
+        bq.size += other.size
 
-    oroot := other.root
-    oroot.moveChildrenToSubqueue()
+        oroot := other.root
+        oroot.moveChildrenToSubqueue()
+        oroot.index = nil
+        oroot.rank = 0  // Without its children, it is a singleton now.
+        other.root = nil
+        other.size = 0
 
-    bq.insert(oroot)
+        bq.insert(oroot)
+    }
+
+    if bq.recorder != nil {
+        bq.recorder.recordMerge(bq.id, other.recorder, other.id)
+    }
 }
 
 
@@ -127,9 +201,16 @@ This method performs the actual insertion operation. It is a bit complicated bec
 func (bq* BOHeap) insert(newnode *BONode) {
     if bq.root == nil {
         // When the queue is empty.
+        bq.index.attach(newnode)
         bq.root = newnode
-    } else if newnode.value < bq.root.value {  // If you put "<=" instead of "<", an infinite loop occurs. Find why.
+    } else if bq.order.before(newnode, bq.root) {  // If you put "<=" instead of "<", an infinite loop occurs. Find why.
         oldroot := bq.swapWithRoot(newnode)
+        if bq.stats != nil {
+            bq.stats.RootSwaps += 1
+        }
+        if bq.tracer != nil {
+            bq.tracer.OnSwapRoot(oldroot.value, newnode.value)
+        }
         bq.insert(oldroot)
     } else {
         if newnode.rank > 0 {
@@ -143,23 +224,44 @@ func (bq* BOHeap) insert(newnode *BONode) {
 
 /*
 Merge the subqueue. Essentially, re-insert the immediate children, but for the subqueue.
+Returns the amount of nodes re-inserted.
  */
-func (bq* BOHeap) merge_subqueue() {
-    for _, node := range bq.root.subqueueIterator() {
+func (bq* BOHeap) merge_subqueue() int {
+    root := bq.root
+    if bq.tracer != nil {
+        bq.tracer.OnSubqueueMerge(root.value, listLength(root.subqueue_head))
+    }
+
+    // Always take the head of the subqueue. Going rogue unlinks it, so we never hold on to a node whose siblings are
+    // about to change. The subqueued nodes are not smaller than the root, so the root stays where it is.
+    merged := 0
+    for root.subqueue_head != nil {
+        node := root.subqueue_head
         node.rogue_subqueue()
         bq.insert(node)
+        merged += 1
+    }
+
+    if bq.stats != nil {
+        bq.stats.SubqueueMerges += 1
     }
+    return merged
 }
 
 /*
 Re-insert the children. Simple.
+Returns the amount of nodes re-inserted.
  */
-func (bq* BOHeap) reInsertChildren(bon *BONode) {
-    // Reinsert the children of the GIVEN node.
-    for _, node := range bon.childrenIterator() {
+func (bq* BOHeap) reInsertChildren(bon *BONode) int {
+    // Reinsert the children of the GIVEN node. Same head-taking loop as in "merge_subqueue".
+    reinserted := 0
+    for bon.children_head != nil {
+        node := bon.children_head
         node.rogue()
         bq.insert(node)
+        reinserted += 1
     }
+    return reinserted
 }
 
 
@@ -173,7 +275,16 @@ This function performs skew-binomial insertion, which is;
  */
 func (bq* BOHeap) insert_skew(newnode *BONode) {
     node1, node2 := bq.root.getSmallestRankChildren()
-    mergednode := skewLink(node1, node2, newnode)
+    mergednode := skewLink(bq.order, node1, node2, newnode)
+    if mergednode.rank > 0 {
+        // skewLink hands "newnode" back untouched, with rank 0, when there was nothing to link.
+        if bq.stats != nil {
+            bq.stats.SkewLinks += 1
+        }
+        if bq.tracer != nil {
+            bq.traceSkewLink(mergednode, node1, node2, newnode)
+        }
+    }
     bq.root.adopt(mergednode)
 }
 
@@ -205,10 +316,25 @@ func (bq* BOHeap) insert_binomial(other *BONode) {
     if srnode == nil {
         bq.root.adopt(other)
     } else {
-        newnode := simpleLink(srnode, other)
+        newnode := simpleLink(bq.order, srnode, other)
+        if bq.tracer != nil {
+            child := srnode
+            if newnode == srnode {
+                child = other
+            }
+            bq.tracer.OnSimpleLink(newnode.value, child.value, newnode.rank)
+        }
+        if bq.stats != nil {
+            bq.stats.SimpleLinks += 1
+            if bq.relinking {
+                bq.stats.BinomialCascades += 1
+            }
+            bq.relinking = true
+        }
         // There is a recursion here. If the aforementioned cascading to be occur, it shall be done so by recursing
         // the next line of code. Figure it out yourself.
         bq.insert(newnode)
+        bq.relinking = false
     }
 }
 
@@ -217,12 +343,15 @@ func (bq* BOHeap) insert_binomial(other *BONode) {
 Swap the other node with the existing root.
  */
 func (bq* BOHeap) swapWithRoot(newroot *BONode) *BONode {
-    // "newroot" is assumed to be rogue and childless.
+    // "newroot" is assumed to be rogue. It may have children of its own if it comes out of a binomial link.
     oldroot := bq.root
+    oldroot.index = nil
+    bq.index.attach(newroot)
 
     // Since the children has a reference to their parents, we need to iterate through all of them anyway.
     // Simply transferring "children_head" won't work.
-    for _, node := range oldroot.childrenIterator() {
+    for oldroot.children_head != nil {
+        node := oldroot.children_head
         node.rogue()
         newroot.adopt(node)
     }
@@ -238,9 +367,18 @@ func (bq* BOHeap) swapWithRoot(newroot *BONode) *BONode {
 Promote the selected node to be the new root.
  */
 func (bq* BOHeap) promoteToRoot(minnode *BONode) {
-    // There is a bug. What is it?
     bq.root.value = minnode.value
+    bq.root.seq = minnode.seq
     bq.root.subqueue_head = minnode.subqueue_head
+
+    // The subqueued nodes still point to "minnode" as their parent, which is about to be thrown away.
+    for node := bq.root.subqueue_head; node != nil; node = node.rightsibling {
+        node.parent = bq.root
+    }
+
+    if bq.tracer != nil {
+        bq.tracer.OnPromote(minnode.value)
+    }
 }
 
 
@@ -0,0 +1,100 @@
+package BrodalOkasakiHeap
+
+
+import (
+    "container/heap"
+    "testing"
+)
+
+
+// Reference model, a plain binary heap from the standard library.
+type refHeap []int
+
+func (rh refHeap) Len() int				{ return len(rh) }
+func (rh refHeap) Less(i, j int) bool	{ return rh[i] < rh[j] }
+func (rh refHeap) Swap(i, j int)		{ rh[i], rh[j] = rh[j], rh[i] }
+func (rh *refHeap) Push(x interface{})	{ *rh = append(*rh, x.(int)) }
+func (rh *refHeap) Pop() interface{} {
+    old := *rh
+    x := old[len(old)-1]
+    *rh = old[:len(old)-1]
+    return x
+}
+
+
+/*
+Every pair of bytes is an operation on one of two heaps: the first byte picks the operation and the heap, the second
+byte is the key for insertions. Keys are squeezed into a small range so duplicates are common.
+ */
+func FuzzBOHeap(f *testing.F) {
+    f.Add([]byte{})
+    f.Add([]byte{0, 1, 0, 2, 0, 3, 0, 4, 2, 0, 2, 0})
+    f.Add([]byte{0, 5, 0, 5, 0, 5, 1, 5, 1, 5, 4, 0, 2, 0, 2, 0, 2, 0})
+    f.Add([]byte{1, 9, 1, 8, 1, 7, 0, 1, 0, 2, 4, 0, 0, 0, 2, 0, 5, 0, 3, 0})
+    f.Add([]byte{2, 0, 3, 0, 6, 0, 4, 0, 5, 0})
+
+    f.Fuzz(func(t *testing.T, ops []byte) {
+        heaps := [2]*BOHeap{NewBOHeap(), NewBOHeap()}
+        refs := [2]*refHeap{{}, {}}
+
+        for i := 0; i+1 < len(ops); i += 2 {
+            op := ops[i] % 8
+            which := int(op & 1)
+            bq, ref := heaps[which], refs[which]
+            key := int(ops[i+1] % 16) - 8
+
+            switch op >> 1 {
+            case 0:
+                bq.Insert(key)
+                heap.Push(ref, key)
+
+            case 1:
+                if ref.Len() == 0 {
+                    mustPanic(t, func() { bq.Pop() })
+                    break
+                }
+                expected := heap.Pop(ref).(int)
+                if popped := bq.Pop(); popped != expected {
+                    t.Fatalf("op %d: popped %d, expected %d", i/2, popped, expected)
+                }
+
+            case 2:
+                if ref.Len() == 0 {
+                    mustPanic(t, func() { bq.Peek() })
+                    break
+                }
+                if peeked := bq.Peek(); peeked != (*ref)[0] {
+                    t.Fatalf("op %d: peeked %d, expected %d", i/2, peeked, (*ref)[0])
+                }
+
+            case 3:
+                // Merge the other heap into this one, leaving the other one empty.
+                other, oref := heaps[1-which], refs[1-which]
+                bq.Merge(other)
+                for oref.Len() > 0 {
+                    heap.Push(ref, heap.Pop(oref))
+                }
+            }
+
+            for h := range heaps {
+                if heaps[h].Size() != refs[h].Len() {
+                    t.Fatalf("op %d: heap %d has size %d, expected %d", i/2, h, heaps[h].Size(), refs[h].Len())
+                }
+                if err := heaps[h].Validate(); err != nil {
+                    t.Fatalf("op %d: heap %d: %v", i/2, h, err)
+                }
+            }
+        }
+    })
+}
+
+
+func mustPanic(t *testing.T, f func()) {
+    t.Helper()
+    defer func() {
+        if recover() == nil {
+            t.Fatalf("operation on an empty heap did not panic")
+        }
+    }()
+    f()
+}
@@ -326,6 +326,38 @@ func Test_merge_1(t *testing.T) {
 }
 
 
+func Test_merge_subqueue(t *testing.T) {
+    h1 := NewBOHeap()
+    h2 := NewBOHeap()
+    h3 := NewBOHeap()
+    insert_mult(h1, interval(0, 10))
+    insert_mult(h2, interval(10, 20))
+    insert_mult(h3, interval(20, 30))
+
+    // The root of h2 becomes the root of h3, its subqueue along with it. Merging h3 then puts the children of that root
+    // in front of the subqueue it already holds.
+    h3.Merge(h2)
+    h1.Merge(h3)
+    if err := h1.Validate(); err != nil {t.Fatal(err)}
+
+    for i:=0; i<30; i++ {
+        if pval := h1.Pop(); pval != i {t.Fatalf("expected %d, got %d", i, pval)}
+    }
+}
+
+
+func Test_merge_self(t *testing.T) {
+    h := NewBOHeap()
+    insert_mult(h, interval(0, 10))
+
+    mustPanic(t, func() { h.Merge(h) })
+    if h.Size() != 10 {t.Errorf("size error, expected 10, got %d", h.Size())}
+    for i:=0; i<10; i++ {
+        if pval := h.Pop(); pval != i {t.Errorf("expected %d, got %d", i, pval)}
+    }
+}
+
+
 func Test_merge_shuffle(t *testing.T) {
     const (
         SIZE1 = 1000
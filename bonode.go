@@ -25,6 +25,12 @@ type BONode struct {
 
     // Rank of a node.
     rank			int
+
+    // Insertion sequence number breaking ties between equal values in a stable heap, 0 otherwise. Read "stable.go".
+    seq				uint64
+
+    // Only set for the root of a heap, read "rankIndex".
+    index			*rankIndex
 }
 
 
@@ -57,7 +63,11 @@ func (bon* BONode) adopt(other *BONode) {
     other.parent = bon
 
     // Sibling relations
-    bon.putNodeAmongChildren(other)
+    if bon.index != nil {
+        bon.index.insert(bon, other)
+    } else {
+        bon.putNodeAmongChildren(other)
+    }
 }
 
 
@@ -110,8 +120,13 @@ func (bon* BONode) rogue() {
     }
     parent := bon.parent
 
-    if parent.children_head == bon {
+    if parent.index != nil {
+        parent.index.remove(parent, bon)
+    } else if parent.children_head == bon {
         parent.children_head = bon.rightsibling  // This can set "nil" to parent.children_head
+        if bon.rightsibling != nil {
+            bon.rightsibling.leftsibling = nil
+        }
     } else {
         bon.leftsibling.rightsibling = bon.rightsibling
         if bon.rightsibling != nil {
@@ -135,6 +150,9 @@ func (bon* BONode) rogue_subqueue() {
 
     if parent.subqueue_head == bon {
         parent.subqueue_head = bon.rightsibling
+        if bon.rightsibling != nil {
+            bon.rightsibling.leftsibling = nil
+        }
     } else {
         bon.leftsibling.rightsibling = bon.rightsibling
         if bon.rightsibling != nil {
@@ -150,9 +168,9 @@ func (bon* BONode) rogue_subqueue() {
 /*
 Essential skew-linking procedure is described for "BOHeap.insert_skew"
 
-This function simply performs the linking procedure given the nodes.
+This function simply performs the linking procedure given the nodes. "Minimum" means first in the given order.
  */
-func skewLink(firnode *BONode, secnode *BONode, newnode *BONode) *BONode {
+func skewLink(order Order, firnode *BONode, secnode *BONode, newnode *BONode) *BONode {
     if firnode == nil || secnode == nil {
         // This happens when the parent has less than 2 children.
         return newnode
@@ -164,7 +182,7 @@ func skewLink(firnode *BONode, secnode *BONode, newnode *BONode) *BONode {
         // Get the minimum valued node among those 3, make her parent and the other two her children.
         currRank := firnode.rank  // We can also use secnode to get this value.
 
-        minnode, node1, node2 := min_of_3(firnode, secnode, newnode)
+        minnode, node1, node2 := min_of_3(order, firnode, secnode, newnode)
 
         minnode.rogue()
         node1.rogue()
@@ -185,10 +203,10 @@ This function links the two nodes according to binomilal heap linking procedure.
 
 The returning node is assumed to be rogue for code-simplifying reasons.
  */
-func simpleLink(existingnode *BONode, newnode *BONode) *BONode {
+func simpleLink(order Order, existingnode *BONode, newnode *BONode) *BONode {
     existingnode.rogue()
 
-    if existingnode.value < newnode.value {
+    if order.before(existingnode, newnode) {
         existingnode.adopt(newnode)
         existingnode.rank += 1
         return existingnode
@@ -201,9 +219,9 @@ func simpleLink(existingnode *BONode, newnode *BONode) *BONode {
 
 
 /*
-Return the minimum-valued child.
+Return the minimum-valued child, which is the first child in the given order.
  */
-func (bon* BONode) getMinChild() *BONode {
+func (bon* BONode) getMinChild(order Order) *BONode {
     if !bon.hasChildren() {
         return nil
     }
@@ -212,7 +230,7 @@ func (bon* BONode) getMinChild() *BONode {
     checknode := minchild.rightsibling
 
     for checknode != nil {
-        if checknode.value < minchild.value {
+        if order.before(checknode, minchild) {
             minchild = checknode
         }
         checknode = checknode.rightsibling
@@ -245,6 +263,10 @@ func (bon* BONode) getSmallestRankChildren() (*BONode, *BONode) {
 Simple.
  */
 func (bon* BONode) getSameRankChild(rank int) *BONode {
+    if bon.index != nil {
+        return bon.index.first[rank]
+    }
+
     child := bon.children_head
     for child != nil {
         if child.rank == rank {
@@ -301,15 +323,15 @@ First returning value is the minimum, other two are the rest.
 I decided to return all of them in a sense, because if we don't do that, we need to do additional work back in the
 calling function to determine which one was the smallest.
  */
-func min_of_3(n1 *BONode, n2 *BONode, n3 *BONode) (*BONode, *BONode, *BONode) {
-    if n1.value < n2.value {
-        if n1.value < n3.value {
+func min_of_3(order Order, n1 *BONode, n2 *BONode, n3 *BONode) (*BONode, *BONode, *BONode) {
+    if order.before(n1, n2) {
+        if order.before(n1, n3) {
             return n1, n2, n3
         } else {  // n3 <= n1 <= n2
             return n3, n1, n2
         }
     } else {  // n2 <= n1 ? n3
-        if n2.value < n3.value {
+        if order.before(n2, n3) {
             return n2, n1, n3
         } else {  // n3 <= n2 <= n1
             return n3, n1, n2
@@ -319,39 +341,38 @@ func min_of_3(n1 *BONode, n2 *BONode, n3 *BONode) (*BONode, *BONode, *BONode) {
 
 
 /*
-Put subqueue elements to a container, and return them.
-I use this to iterate over elements. We can't trust traversing the children-linked-list because the operations that
-may be performed can modify this list, hence we may not traverse all of the elements.
+Count the nodes of a sibling list, starting from "head".
  */
-func (bon* BONode) subqueueIterator() []*BONode {
-    itercont := make([]*BONode, 0, 8)
-
-    child := bon.subqueue_head
-    for child != nil {
-        itercont = append(itercont, child)
-        child = child.rightsibling
+func listLength(head *BONode) int {
+    length := 0
+    for node := head; node != nil; node = node.rightsibling {
+        length += 1
     }
-    return itercont
+    return length
 }
 
 
-/*
-Read subqueueIterator. Same thing for the children.
- */
-func (bon* BONode) childrenIterator() []*BONode {
-    itercont := make([]*BONode, 0, 8)
-
-    child := bon.children_head
-    for child != nil {
-        itercont = append(itercont, child)
-        child = child.rightsibling
+func (bon* BONode) moveChildrenToSubqueue() {
+    // The node may still hold the subqueue of an earlier merge, so we put the children in front of it instead of
+    // overwriting it.
+    if bon.children_head == nil {
+        return
     }
-    return itercont
-}
 
-
-func (bon* BONode) moveChildrenToSubqueue() {
-    // There is a bug here. Care to find out?
+    // A root knows its last child, so Merge() doesn't have to walk the list to find it.
+    var tail *BONode
+    if bon.index != nil {
+        tail = bon.index.tail
+    } else {
+        tail = bon.children_head
+        for tail.rightsibling != nil {
+            tail = tail.rightsibling
+        }
+    }
+    tail.rightsibling = bon.subqueue_head
+    if bon.subqueue_head != nil {
+        bon.subqueue_head.leftsibling = tail
+    }
 
     bon.subqueue_head = bon.children_head
     bon.children_head = nil
@@ -0,0 +1,168 @@
+package BrodalOkasakiHeap
+
+import "errors"
+
+
+/*
+What a bounded heap does with a key offered while it is full.
+ */
+type OverflowPolicy int
+
+const (
+    RejectNew	OverflowPolicy = iota	// Drop the new key.
+    EvictWorst							// Drop the worst key, which may be the new key itself.
+    FailWhenFull						// Drop the new key and report ErrFull.
+)
+
+
+var ErrFull = errors.New("BoundedBOHeap: heap is full")
+
+
+/*
+A heap that never holds more than a fixed amount of keys. Keys pop in the given order like in any other heap, the
+"worst" key is the one that would pop last.
+
+Evicting the worst key means popping from the wrong end of the heap, so a bounded heap is built on a
+"DoubleEndedBOHeap". That one compacts itself before its gone keys outnumber the live ones, so the memory is bounded by
+the capacity too, however many keys are evicted.
+ */
+type BoundedBOHeap struct {
+    keys		*DoubleEndedBOHeap
+    order		Order
+    capacity	int
+    policy		OverflowPolicy
+}
+
+
+/*
+Create a new heap of at most "capacity" keys, popping in the given order.
+ */
+func NewBoundedBOHeap(capacity int, order Order, policy OverflowPolicy) *BoundedBOHeap {
+    if capacity < 1 {
+        panic("BoundedBOHeap: capacity must be positive")
+    }
+
+    return &BoundedBOHeap {
+        keys: NewDoubleEndedBOHeap(),
+        order: order,
+        capacity: capacity,
+        policy: policy,
+    }
+}
+
+
+/*
+Insert a key according to the overflow policy.
+
+If a key had to be dropped, it is returned along with "dropped" set, which is the new key itself for RejectNew and for
+EvictWorst when the new key is not better than the worst one. FailWhenFull drops nothing and returns ErrFull instead.
+ */
+func (bh *BoundedBOHeap) Offer(value int) (evicted int, dropped bool, err error) {
+    if bh.keys.Size() < bh.capacity {
+        bh.keys.Insert(value)
+        return 0, false, nil
+    }
+
+    switch bh.policy {
+    case EvictWorst:
+        if !bh.order.beforeKey(value, bh.PeekWorst()) {
+            return value, true, nil
+        }
+        evicted = bh.PopWorst()
+        bh.keys.Insert(value)
+        return evicted, true, nil
+
+    case FailWhenFull:
+        return 0, false, ErrFull
+
+    default:
+        return value, true, nil
+    }
+}
+
+
+/*
+Offer the key, ignoring the outcome. Satisfies the PriorityQueue interface, use Offer() to see what happened.
+ */
+func (bh *BoundedBOHeap) Insert(value int) {
+    bh.Offer(value)
+}
+
+
+/*
+Pop the best key, the first one in the order of the heap.
+ */
+func (bh *BoundedBOHeap) Pop() int {
+    if bh.order == MaxFirst {
+        return bh.keys.PopMax()
+    }
+    return bh.keys.PopMin()
+}
+
+
+func (bh *BoundedBOHeap) Peek() int {
+    if bh.order == MaxFirst {
+        return bh.keys.PeekMax()
+    }
+    return bh.keys.PeekMin()
+}
+
+
+/*
+Pop the worst key, the last one in the order of the heap.
+ */
+func (bh *BoundedBOHeap) PopWorst() int {
+    if bh.order == MaxFirst {
+        return bh.keys.PopMin()
+    }
+    return bh.keys.PopMax()
+}
+
+
+func (bh *BoundedBOHeap) PeekWorst() int {
+    if bh.order == MaxFirst {
+        return bh.keys.PeekMin()
+    }
+    return bh.keys.PeekMax()
+}
+
+
+func (bh *BoundedBOHeap) Size() int {
+    return bh.keys.Size()
+}
+
+
+func (bh *BoundedBOHeap) Capacity() int {
+    return bh.capacity
+}
+
+
+/*
+Merge the keys of another *BoundedBOHeap of the same order, which is left empty. Whatever the policy, the worst keys
+are evicted until the heap is back within its capacity.
+ */
+func (bh *BoundedBOHeap) Merge(pq PriorityQueue) {
+    other, ok := pq.(*BoundedBOHeap)
+    if !ok {
+        panic("BoundedBOHeap: can only merge with another *BoundedBOHeap")
+    }
+    bh.merge(other, nil)
+}
+
+
+/*
+Merge, calling "evict" (when not nil) with every key evicted to get back within capacity.
+ */
+func (bh *BoundedBOHeap) merge(other *BoundedBOHeap, evict func(int)) {
+    if other.order != bh.order {
+        panic("BoundedBOHeap: can't merge heaps of different order")
+    }
+
+    bh.keys.Merge(other.keys)
+    for bh.keys.Size() > bh.capacity {
+        evicted := bh.PopWorst()
+        if evict != nil {
+            evict(evicted)
+        }
+    }
+}
@@ -0,0 +1,103 @@
+package BrodalOkasakiHeap
+
+
+import (
+    "testing"
+    "math/rand"
+)
+
+
+func Test_bounded_reject(t *testing.T) {
+    bh := NewBoundedBOHeap(3, MinFirst, RejectNew)
+    insert_mult_pq(bh, []int{5, 1, 3})
+
+    evicted, dropped, err := bh.Offer(0)
+    if evicted != 0 || !dropped || err != nil {t.Errorf("expected 0 to be rejected, got %d %t %v", evicted, dropped, err)}
+    if bh.Size() != 3 || bh.Peek() != 1 {t.Errorf("rejected key made it in")}
+}
+
+
+func Test_bounded_error(t *testing.T) {
+    bh := NewBoundedBOHeap(2, MinFirst, FailWhenFull)
+    insert_mult_pq(bh, []int{5, 1})
+
+    if _, dropped, err := bh.Offer(0); dropped || err != ErrFull {t.Errorf("expected ErrFull, got %t %v", dropped, err)}
+    if bh.Size() != 2 {t.Errorf("expected size 2, got %d", bh.Size())}
+
+    bh.Pop()
+    if _, _, err := bh.Offer(0); err != nil {t.Errorf("offer after making room failed: %v", err)}
+    if bh.Peek() != 0 {t.Errorf("expected 0 on top, got %d", bh.Peek())}
+}
+
+
+func Test_bounded_evict_worst(t *testing.T) {
+    bh := NewBoundedBOHeap(3, MinFirst, EvictWorst)
+    insert_mult_pq(bh, []int{5, 1, 3})
+
+    // 0 is better than the worst key 5, which goes.
+    if evicted, dropped, _ := bh.Offer(0); evicted != 5 || !dropped {t.Errorf("expected 5 evicted, got %d %t", evicted, dropped)}
+
+    // 4 is worse than anything inside, so it goes itself.
+    if evicted, dropped, _ := bh.Offer(4); evicted != 4 || !dropped {t.Errorf("expected 4 dropped, got %d %t", evicted, dropped)}
+
+    for _, expected := range []int{0, 1, 3} {
+        if pval := bh.Pop(); pval != expected {t.Errorf("expected %d, got %d", expected, pval)}
+    }
+}
+
+
+func Test_bounded_keeps_best(t *testing.T) {
+    const (
+        SIZE = 1000
+        CAPACITY = 10
+    )
+    rand.Seed(1)
+
+    // A max-ordered heap keeping the 10 largest keys.
+    bh := NewBoundedBOHeap(CAPACITY, MaxFirst, EvictWorst)
+    for _, elem := range shuffle(interval(0, SIZE)) {
+        bh.Offer(elem)
+        if bh.Size() > CAPACITY {t.Fatalf("size %d over capacity", bh.Size())}
+    }
+
+    if bh.PeekWorst() != SIZE-CAPACITY {t.Errorf("expected worst %d, got %d", SIZE-CAPACITY, bh.PeekWorst())}
+    for i:=SIZE-1; i>=SIZE-CAPACITY; i-- {
+        if pval := bh.Pop(); pval != i {t.Errorf("expected %d, got %d", i, pval)}
+    }
+}
+
+
+func Test_bounded_merge(t *testing.T) {
+    b1 := NewBoundedBOHeap(5, MinFirst, RejectNew)
+    b2 := NewBoundedBOHeap(5, MinFirst, RejectNew)
+    insert_mult_pq(b1, []int{1, 3, 5, 7, 9})
+    insert_mult_pq(b2, []int{0, 2, 4, 6, 8})
+
+    b1.Merge(b2)
+    if b1.Size() != 5 {t.Errorf("expected size 5, got %d", b1.Size())}
+    for i:=0; i<5; i++ {
+        if pval := b1.Pop(); pval != i {t.Errorf("expected %d, got %d", i, pval)}
+    }
+}
+
+
+func Test_bounded_memory(t *testing.T) {
+    const CAPACITY = 10
+    rng := rand.New(rand.NewSource(1))
+
+    // Evicted keys must not pile up anywhere inside, from offers or from merges.
+    for _, order := range []Order{MinFirst, MaxFirst} {
+        bh := NewBoundedBOHeap(CAPACITY, order, EvictWorst)
+        for i:=0; i<100000; i++ {
+            bh.Offer(rng.Intn(1 << 20))
+            if i % 100 == 0 {
+                other := NewBoundedBOHeap(CAPACITY, order, EvictWorst)
+                insert_mult_pq(other, shuffle(interval(0, CAPACITY)))
+                bh.Merge(other)
+            }
+
+            dq := bh.keys
+            if dq.min.Size() > 2*CAPACITY+1 || dq.max.Size() > 2*CAPACITY+1 {t.Fatalf("op %d: heaps of %d and %d nodes for capacity %d", i, dq.min.Size(), dq.max.Size(), CAPACITY)}
+        }
+    }
+}
@@ -0,0 +1,760 @@
+package BrodalOkasakiHeap
+
+import (
+    "fmt"
+    "math"
+    "sync/atomic"
+)
+
+
+/*
+"CompactBOHeap" is the same Brodal-Okasaki heap as "BOHeap", stored differently. Instead of allocating every node on
+its own, the nodes live in the slice of a "CompactArena" and refer to each other by their int32 index within it.
+
+    * A node takes 32 bytes instead of the 72 bytes of a "BONode".
+    * The slice holds no pointers, so the garbage collector never has to scan it.
+    * Freed slots are reused by following inserts, so an arena is a node pool as well.
+
+Index 0 is never used for a node, so that a zero link means "no node", just like nil does for "BONode". The algorithm
+is exactly the one of "BOHeap", read the comments over there for the reasoning; the functions here have the same names.
+So are the extras: the Order, stable mode, Stats(), the Tracer and the Recorder.
+
+Heaps of the same arena merge in O(1) time, just like BOHeaps, since the nodes of the other heap are already where they
+need to be. Heaps of different arenas can be merged too, but then the nodes of the other heap have to be copied over,
+which takes O(m) time for a heap of m nodes. NewCompactBOHeap() makes a heap with an arena of its own, so share an
+arena between the heaps that are going to be merged.
+ */
+type CompactBOHeap struct {
+    arena	*CompactArena
+    root	int32
+    tail	int32		// The last child of the root, so Merge() doesn't have to walk the list. Read "rankIndex".
+    size	int
+    order	Order
+
+    // Same as the fields of "BOHeap".
+    stats		*Stats
+    relinking	bool
+    tracer		Tracer
+    recorder	*Recorder
+    id			int
+    stable		bool
+}
+
+
+/*
+The nodes of any amount of CompactBOHeaps, in a single slice. Heaps of the same arena merge in O(1) time.
+
+Freed slots are chained through "right", and are reused before the slice grows. Unlike the pool of NewPooledBOHeap(),
+an arena keeps every freed slot, since a slot in the middle of the slice can't be given back on its own. Shrink() gives
+back the free slots at the end.
+
+The heaps of an arena share it, so they are not safe for concurrent use, not even two different heaps.
+ */
+type CompactArena struct {
+    nodes	[]cnode
+    seqs	[]uint64	// Sequence numbers of the nodes, read "stableSeq". nil until a stable heap inserts a node.
+    free	int32		// Head of the free slots.
+}
+
+
+/*
+A node of "CompactBOHeap". Same fields as "BONode", with indices in place of pointers. The sequence number of a stable
+node is in "CompactArena.seqs" instead, so that the other nodes don't pay for it.
+ */
+type cnode struct {
+    value		int
+    rank		int32
+
+    subqueue	int32
+    children	int32
+
+    parent		int32
+    right		int32
+    left		int32
+}
+
+
+// The rank of a free slot, so that Shrink() can tell them apart from nodes.
+const freeRank = -1
+
+
+/*
+Create a new arena, with room for "capacity" nodes before the slice has to grow.
+ */
+func NewCompactArena(capacity int) *CompactArena {
+    if capacity < 0 {
+        capacity = 0
+    }
+    return &CompactArena {
+        nodes: make([]cnode, 1, capacity+1),
+    }
+}
+
+
+/*
+Create a new heap in the arena, popping its keys in the given order.
+ */
+func (a *CompactArena) NewBOHeap(order Order) *CompactBOHeap {
+    return &CompactBOHeap {
+        arena: a,
+        order: order,
+    }
+}
+
+
+/*
+Create a new heap in the arena that pops equal keys in the order they were inserted. Read NewStableBOHeap().
+ */
+func (a *CompactArena) NewStableBOHeap(order Order) *CompactBOHeap {
+    ch := a.NewBOHeap(order)
+    ch.stable = true
+    return ch
+}
+
+
+/*
+Give the free slots at the end of the slice back to the runtime, and chain the other free slots anew. Takes O(n) time
+for the n slots of the arena.
+ */
+func (a *CompactArena) Shrink() {
+    end := len(a.nodes)
+    for end > 1 && a.nodes[end-1].rank == freeRank {
+        end -= 1
+    }
+
+    a.free = 0
+    for n := int32(end - 1); n > 0; n-- {
+        if a.nodes[n].rank == freeRank {
+            a.nodes[n].right = a.free
+            a.free = n
+        }
+    }
+
+    nodes := make([]cnode, end)
+    copy(nodes, a.nodes)
+    a.nodes = nodes
+    if a.seqs != nil {
+        seqs := make([]uint64, end)
+        copy(seqs, a.seqs)
+        a.seqs = seqs
+    }
+}
+
+
+/*
+Create a new compact Brodal-Okasaki heap, with an arena of its own.
+ */
+func NewCompactBOHeap() *CompactBOHeap {
+    return NewCompactArena(0).NewBOHeap(MinFirst)
+}
+
+
+/*
+Return the arena the nodes of the heap live in.
+ */
+func (ch *CompactBOHeap) Arena() *CompactArena {
+    return ch.arena
+}
+
+
+func (ch *CompactBOHeap) Order() Order {
+    return ch.order
+}
+
+
+func (ch *CompactBOHeap) Stable() bool {
+    return ch.stable
+}
+
+
+func (ch *CompactBOHeap) Insert(value int) {
+    var seq uint64
+    if ch.stable {
+        seq = atomic.AddUint64(&stableSeq, 1)
+    }
+    n := ch.arena.newNode(value, seq)
+    ch.size += 1
+    ch.insert(n)
+
+    if ch.recorder != nil {
+        ch.recorder.recordInsert(ch.id, value)
+    }
+}
+
+
+func (ch *CompactBOHeap) Pop() int {
+    if ch.root == 0 {
+        panic("CompactBOHeap: Pop() on an empty heap")
+    }
+
+    ch.size -= 1
+    retval := ch.arena.nodes[ch.root].value
+    touched := 0
+
+    if ch.arena.nodes[ch.root].subqueue != 0 {
+        touched += ch.merge_subqueue()
+    }
+
+    minchild := ch.getMinChild(ch.root)
+
+    if minchild == 0 {
+        ch.arena.freeNode(ch.root)
+        ch.root = 0
+        ch.tail = 0
+    } else {
+        ch.rogue(minchild)
+        touched += 1 + ch.reInsertChildren(minchild)
+        ch.promoteToRoot(minchild)
+        ch.arena.freeNode(minchild)
+    }
+
+    if ch.stats != nil {
+        ch.stats.countPop(touched)
+    }
+    if ch.recorder != nil {
+        ch.recorder.recordPop(ch.id, retval)
+    }
+
+    return retval
+}
+
+
+func (ch *CompactBOHeap) Peek() int {
+    if ch.root == 0 {
+        panic("CompactBOHeap: Peek() on an empty heap")
+    }
+    return ch.arena.nodes[ch.root].value
+}
+
+
+func (ch *CompactBOHeap) Size() int {
+    return ch.size
+}
+
+
+/*
+The lazy merge of "BOHeap.Merge". The nodes of a heap of another arena are copied into ours first.
+
+"other" must be another *CompactBOHeap of the same order. Its nodes now belong to this heap, so it is left empty.
+ */
+func (ch *CompactBOHeap) Merge(pq PriorityQueue) {
+    other, ok := pq.(*CompactBOHeap)
+    if !ok {
+        panic("CompactBOHeap: can only merge with another *CompactBOHeap")
+    }
+    if other == ch {
+        panic("CompactBOHeap: can't merge a heap with itself")
+    }
+    if other.order != ch.order {
+        panic("CompactBOHeap: can't merge heaps of different order")
+    }
+
+    if other.root != 0 {
+        ch.size += other.size
+
+        oroot, otail := other.root, other.tail
+        if other.arena != ch.arena {
+            oroot = ch.arena.copyTree(other.arena, oroot, 0)
+            otail = ch.lastChild(oroot)
+        }
+        other.root = 0
+        other.tail = 0
+        other.size = 0
+
+        ch.moveChildrenToSubqueue(oroot, otail)
+        ch.arena.nodes[oroot].rank = 0
+        ch.insert(oroot)
+    }
+
+    if ch.recorder != nil {
+        ch.recorder.recordMerge(ch.id, other.recorder, other.id)
+    }
+}
+
+
+/*
+Same checks as "BOHeap.Validate".
+ */
+func (ch *CompactBOHeap) Validate() error {
+    if ch.root == 0 {
+        if ch.size != 0 {
+            return fmt.Errorf("empty heap with size %d", ch.size)
+        }
+        return nil
+    }
+
+    root := &ch.arena.nodes[ch.root]
+    if root.parent != 0 || root.left != 0 || root.right != 0 {
+        return fmt.Errorf("root %d is linked to other nodes", root.value)
+    }
+    if ch.tail != ch.lastChild(ch.root) {
+        return fmt.Errorf("tail of root %d does not match its children", root.value)
+    }
+
+    count := 0
+    if err := ch.validate(ch.root, &count); err != nil {
+        return err
+    }
+    if count != ch.size {
+        return fmt.Errorf("heap holds %d nodes but has size %d", count, ch.size)
+    }
+    return nil
+}
+
+
+// ====== Helper functions =======
+
+
+func (a *CompactArena) newNode(value int, seq uint64) int32 {
+    var n int32
+    if a.free != 0 {
+        n = a.free
+        a.free = a.nodes[n].right
+        a.nodes[n] = cnode{value: value}
+    } else {
+        if len(a.nodes) > math.MaxInt32 {
+            panic("CompactBOHeap: too many nodes")
+        }
+        a.nodes = append(a.nodes, cnode{value: value})
+        if a.seqs != nil {
+            a.seqs = append(a.seqs, 0)
+        }
+        n = int32(len(a.nodes) - 1)
+    }
+
+    if seq != 0 && a.seqs == nil {
+        a.seqs = make([]uint64, len(a.nodes), cap(a.nodes))
+    }
+    if a.seqs != nil {
+        a.seqs[n] = seq
+    }
+    return n
+}
+
+
+func (a *CompactArena) freeNode(n int32) {
+    a.nodes[n] = cnode{rank: freeRank, right: a.free}
+    a.free = n
+}
+
+
+func (a *CompactArena) seq(n int32) uint64 {
+    if a.seqs == nil {
+        return 0
+    }
+    return a.seqs[n]
+}
+
+
+/*
+Copy node "n" of arena "from", and everything below it, into this arena, freeing the slots over there. Returns the
+index of the copy, which becomes a child of "parent".
+ */
+func (a *CompactArena) copyTree(from *CompactArena, n int32, parent int32) int32 {
+    src := from.nodes[n]
+    c := a.newNode(src.value, from.seq(n))
+    children := a.copyList(from, src.children, c)
+    subqueue := a.copyList(from, src.subqueue, c)
+    from.freeNode(n)
+
+    a.nodes[c].rank = src.rank
+    a.nodes[c].parent = parent
+    a.nodes[c].children = children
+    a.nodes[c].subqueue = subqueue
+    return c
+}
+
+
+/*
+Same as "copyTree" for a sibling list, returning the head of the copy.
+ */
+func (a *CompactArena) copyList(from *CompactArena, head int32, parent int32) int32 {
+    first, prev := int32(0), int32(0)
+    for n := head; n != 0; {
+        next := from.nodes[n].right
+        c := a.copyTree(from, n, parent)
+
+        a.nodes[c].left = prev
+        if prev == 0 {
+            first = c
+        } else {
+            a.nodes[prev].right = c
+        }
+        prev = c
+        n = next
+    }
+    return first
+}
+
+
+/*
+Read "Order.before".
+ */
+func (ch *CompactBOHeap) before(n1 int32, n2 int32) bool {
+    v1, v2 := ch.arena.nodes[n1].value, ch.arena.nodes[n2].value
+    if v1 == v2 {
+        return ch.arena.seq(n1) < ch.arena.seq(n2)
+    }
+    return ch.order.beforeKey(v1, v2)
+}
+
+
+func (ch *CompactBOHeap) insert(n int32) {
+    if ch.root == 0 {
+        ch.root = n
+        ch.tail = ch.lastChild(n)
+    } else if ch.before(n, ch.root) {
+        oldroot := ch.swapWithRoot(n)
+        if ch.stats != nil {
+            ch.stats.RootSwaps += 1
+        }
+        if ch.tracer != nil {
+            ch.tracer.OnSwapRoot(ch.arena.nodes[oldroot].value, ch.arena.nodes[n].value)
+        }
+        ch.insert(oldroot)
+    } else if ch.arena.nodes[n].rank > 0 {
+        ch.insert_binomial(n)
+    } else {
+        ch.insert_skew(n)
+    }
+}
+
+
+func (ch *CompactBOHeap) merge_subqueue() int {
+    // The root can't change here, read "BOHeap.merge_subqueue".
+    root := ch.root
+    if ch.tracer != nil {
+        ch.tracer.OnSubqueueMerge(ch.arena.nodes[root].value, ch.listLength(ch.arena.nodes[root].subqueue))
+    }
+
+    merged := 0
+    for ch.arena.nodes[root].subqueue != 0 {
+        n := ch.arena.nodes[root].subqueue
+        ch.rogue_subqueue(n)
+        ch.insert(n)
+        merged += 1
+    }
+
+    if ch.stats != nil {
+        ch.stats.SubqueueMerges += 1
+    }
+    return merged
+}
+
+
+func (ch *CompactBOHeap) reInsertChildren(parent int32) int {
+    reinserted := 0
+    for ch.arena.nodes[parent].children != 0 {
+        n := ch.arena.nodes[parent].children
+        ch.rogue(n)
+        ch.insert(n)
+        reinserted += 1
+    }
+    return reinserted
+}
+
+
+func (ch *CompactBOHeap) insert_skew(n int32) {
+    first := ch.arena.nodes[ch.root].children
+    second := int32(0)
+    if first != 0 {
+        second = ch.arena.nodes[first].right
+    }
+
+    merged := ch.skewLink(first, second, n)
+    if ch.arena.nodes[merged].rank > 0 {
+        if ch.stats != nil {
+            ch.stats.SkewLinks += 1
+        }
+        if ch.tracer != nil {
+            ch.traceSkewLink(merged, first, second, n)
+        }
+    }
+    ch.adopt(ch.root, merged)
+}
+
+
+func (ch *CompactBOHeap) insert_binomial(other int32) {
+    srnode := ch.getSameRankChild(ch.root, ch.arena.nodes[other].rank)
+
+    if srnode == 0 {
+        ch.adopt(ch.root, other)
+    } else {
+        newnode := ch.simpleLink(srnode, other)
+        if ch.tracer != nil {
+            child := srnode
+            if newnode == srnode {
+                child = other
+            }
+            nn := &ch.arena.nodes[newnode]
+            ch.tracer.OnSimpleLink(nn.value, ch.arena.nodes[child].value, int(nn.rank))
+        }
+        if ch.stats != nil {
+            ch.stats.SimpleLinks += 1
+            if ch.relinking {
+                ch.stats.BinomialCascades += 1
+            }
+            ch.relinking = true
+        }
+        ch.insert(newnode)
+        ch.relinking = false
+    }
+}
+
+
+func (ch *CompactBOHeap) swapWithRoot(newroot int32) int32 {
+    oldroot := ch.root
+
+    for ch.arena.nodes[oldroot].children != 0 {
+        n := ch.arena.nodes[oldroot].children
+        ch.rogue(n)
+        ch.adopt(newroot, n)
+    }
+    ch.arena.nodes[oldroot].rank = 0
+
+    ch.root = newroot
+    ch.tail = ch.lastChild(newroot)
+    return oldroot
+}
+
+
+func (ch *CompactBOHeap) promoteToRoot(minnode int32) {
+    root := &ch.arena.nodes[ch.root]
+    root.value = ch.arena.nodes[minnode].value
+    root.subqueue = ch.arena.nodes[minnode].subqueue
+    if ch.arena.seqs != nil {
+        ch.arena.seqs[ch.root] = ch.arena.seqs[minnode]
+    }
+
+    for n := root.subqueue; n != 0; n = ch.arena.nodes[n].right {
+        ch.arena.nodes[n].parent = ch.root
+    }
+
+    if ch.tracer != nil {
+        ch.tracer.OnPromote(root.value)
+    }
+}
+
+
+func (ch *CompactBOHeap) skewLink(first int32, second int32, newnode int32) int32 {
+    if first == 0 || second == 0 || ch.arena.nodes[first].rank != ch.arena.nodes[second].rank {
+        return newnode
+    }
+
+    rank := ch.arena.nodes[first].rank
+    minnode, n1, n2 := ch.min_of_3(first, second, newnode)
+
+    ch.rogue(minnode)
+    ch.rogue(n1)
+    ch.rogue(n2)
+
+    ch.adopt(minnode, n1)
+    ch.adopt(minnode, n2)
+    ch.arena.nodes[minnode].rank = rank + 1
+
+    return minnode
+}
+
+
+func (ch *CompactBOHeap) simpleLink(existing int32, newnode int32) int32 {
+    ch.rogue(existing)
+
+    if ch.before(existing, newnode) {
+        ch.adopt(existing, newnode)
+        ch.arena.nodes[existing].rank += 1
+        return existing
+    } else {
+        ch.adopt(newnode, existing)
+        ch.arena.nodes[newnode].rank += 1
+        return newnode
+    }
+}
+
+
+func (ch *CompactBOHeap) min_of_3(n1 int32, n2 int32, n3 int32) (int32, int32, int32) {
+    if ch.before(n1, n2) {
+        if ch.before(n1, n3) {
+            return n1, n2, n3
+        }
+        return n3, n1, n2
+    } else {
+        if ch.before(n2, n3) {
+            return n2, n1, n3
+        }
+        return n3, n1, n2
+    }
+}
+
+
+/*
+Put "child" among the children of "parent", keeping them rank ordered. Read "BONode.putNodeAmongChildren".
+ */
+func (ch *CompactBOHeap) adopt(parent int32, child int32) {
+    c := &ch.arena.nodes[child]
+    c.parent = parent
+
+    prev := int32(0)
+    next := ch.arena.nodes[parent].children
+    for next != 0 && c.rank > ch.arena.nodes[next].rank {
+        prev = next
+        next = ch.arena.nodes[next].right
+    }
+
+    c.left = prev
+    c.right = next
+    if prev == 0 {
+        ch.arena.nodes[parent].children = child
+    } else {
+        ch.arena.nodes[prev].right = child
+    }
+    if next != 0 {
+        ch.arena.nodes[next].left = child
+    } else if parent == ch.root {
+        ch.tail = child
+    }
+}
+
+
+func (ch *CompactBOHeap) rogue(n int32) {
+    c := &ch.arena.nodes[n]
+    if c.parent == 0 {
+        return
+    }
+    if c.parent == ch.root && c.right == 0 {
+        ch.tail = c.left
+    }
+    ch.unlink(n, &ch.arena.nodes[c.parent].children)
+}
+
+
+func (ch *CompactBOHeap) rogue_subqueue(n int32) {
+    c := &ch.arena.nodes[n]
+    if c.parent == 0 {
+        return
+    }
+    ch.unlink(n, &ch.arena.nodes[c.parent].subqueue)
+}
+
+
+/*
+Take a node out of the sibling list starting at "*head".
+ */
+func (ch *CompactBOHeap) unlink(n int32, head *int32) {
+    c := &ch.arena.nodes[n]
+
+    if c.left == 0 {
+        *head = c.right
+    } else {
+        ch.arena.nodes[c.left].right = c.right
+    }
+    if c.right != 0 {
+        ch.arena.nodes[c.right].left = c.left
+    }
+
+    c.parent = 0
+    c.left = 0
+    c.right = 0
+}
+
+
+func (ch *CompactBOHeap) getMinChild(parent int32) int32 {
+    minchild := ch.arena.nodes[parent].children
+    if minchild == 0 {
+        return 0
+    }
+
+    for n := ch.arena.nodes[minchild].right; n != 0; n = ch.arena.nodes[n].right {
+        if ch.before(n, minchild) {
+            minchild = n
+        }
+    }
+    return minchild
+}
+
+
+func (ch *CompactBOHeap) getSameRankChild(parent int32, rank int32) int32 {
+    for n := ch.arena.nodes[parent].children; n != 0; n = ch.arena.nodes[n].right {
+        if ch.arena.nodes[n].rank == rank {
+            return n
+        }
+    }
+    return 0
+}
+
+
+func (ch *CompactBOHeap) lastChild(parent int32) int32 {
+    last := int32(0)
+    for n := ch.arena.nodes[parent].children; n != 0; n = ch.arena.nodes[n].right {
+        last = n
+    }
+    return last
+}
+
+
+func (ch *CompactBOHeap) listLength(head int32) int {
+    length := 0
+    for n := head; n != 0; n = ch.arena.nodes[n].right {
+        length += 1
+    }
+    return length
+}
+
+
+/*
+Read "BONode.moveChildrenToSubqueue". "tail" is the last child of "n".
+ */
+func (ch *CompactBOHeap) moveChildrenToSubqueue(n int32, tail int32) {
+    c := &ch.arena.nodes[n]
+    if c.children == 0 {
+        return
+    }
+
+    ch.arena.nodes[tail].right = c.subqueue
+    if c.subqueue != 0 {
+        ch.arena.nodes[c.subqueue].left = tail
+    }
+
+    c.subqueue = c.children
+    c.children = 0
+}
+
+
+func (ch *CompactBOHeap) validate(n int32, count *int) error {
+    *count += 1
+    if *count > ch.size {
+        return fmt.Errorf("more than %d nodes reachable", ch.size)
+    }
+
+    if err := ch.validateList(n, ch.arena.nodes[n].children, true, count); err != nil {
+        return err
+    }
+    return ch.validateList(n, ch.arena.nodes[n].subqueue, false, count)
+}
+
+
+func (ch *CompactBOHeap) validateList(parent int32, head int32, ranked bool, count *int) error {
+    prev := int32(0)
+    pvalue := ch.arena.nodes[parent].value
+
+    for n := head; n != 0; n = ch.arena.nodes[n].right {
+        c := &ch.arena.nodes[n]
+        if c.parent != parent {
+            return fmt.Errorf("node %d does not point back to its parent %d", c.value, pvalue)
+        }
+        if c.left != prev {
+            return fmt.Errorf("node %d has a broken left sibling link", c.value)
+        }
+        if ch.before(n, parent) {
+            return fmt.Errorf("node %d comes before its parent %d", c.value, pvalue)
+        }
+        if ranked && prev != 0 && ch.arena.nodes[prev].rank > c.rank {
+            return fmt.Errorf("children of %d are not rank ordered", pvalue)
+        }
+
+        if err := ch.validate(n, count); err != nil {
+            return err
+        }
+        prev = n
+    }
+    return nil
+}
@@ -0,0 +1,291 @@
+package BrodalOkasakiHeap
+
+
+import (
+    "bytes"
+    "testing"
+    "math/rand"
+    "unsafe"
+)
+
+
+func Test_compact_heapsort(t *testing.T) {
+    const SIZE = 1000
+    rand.Seed(1)
+
+    heap := NewCompactBOHeap()
+    for _, elem := range shuffle(interval(0, SIZE)) {
+        heap.Insert(elem)
+    }
+
+    for i:=0; i<SIZE; i++ {
+        if err := heap.Validate(); err != nil {t.Fatalf("%v", err)}
+        pval := heap.Pop()
+        if pval != i {t.Fatalf("expected %d, got %d", i, pval)}
+    }
+}
+
+
+func Test_compact_same_shape(t *testing.T) {
+    // Both storage engines run the same algorithm, so they should agree on the shape of the heap, not only the order.
+    const SIZE = 300
+    rand.Seed(1)
+
+    heap := NewBOHeap()
+    compact := NewCompactBOHeap()
+    for _, elem := range shuffle(interval(0, SIZE)) {
+        heap.Insert(elem)
+        compact.Insert(elem)
+    }
+    for i:=0; i<SIZE/2; i++ {
+        heap.Pop()
+        compact.Pop()
+    }
+
+    if !sameShape(heap.root, compact, compact.root) {t.Errorf("heaps have different shapes")}
+}
+
+
+func Test_compact_merge_arena(t *testing.T) {
+    const SIZE = 100
+
+    arena := NewCompactArena(0)
+    h1 := arena.NewBOHeap(MinFirst)
+    h2 := arena.NewBOHeap(MinFirst)
+    for i:=0; i<SIZE; i++ {
+        h1.Insert(i + SIZE)
+        h2.Insert(i)
+    }
+    for i:=0; i<SIZE/2; i++ {
+        h2.Pop()
+    }
+
+    // The nodes of h2 are relinked where they are, nothing is copied.
+    slots := len(arena.nodes)
+    h1.Merge(h2)
+    if h2.Size() != 0 {t.Errorf("merged heap not emptied")}
+    if len(arena.nodes) != slots {t.Errorf("merge grew the arena from %d to %d slots", slots, len(arena.nodes))}
+    if err := h1.Validate(); err != nil {t.Fatalf("%v", err)}
+
+    // The slots freed in h2 are used first.
+    for i:=0; i<SIZE/2; i++ {
+        h1.Insert(-i)
+    }
+    if len(arena.nodes) != slots {t.Errorf("free slots were not reused, grew from %d to %d", slots, len(arena.nodes))}
+
+    for i:=0; i<SIZE/2; i++ {
+        if pval := h1.Pop(); pval != i-SIZE/2+1 {t.Fatalf("expected %d, got %d", i-SIZE/2+1, pval)}
+    }
+}
+
+
+func Test_compact_merge_other_arena(t *testing.T) {
+    const SIZE = 100
+
+    h1 := NewCompactBOHeap()
+    other := NewCompactArena(0)
+    h2 := other.NewBOHeap(MinFirst)
+    h3 := other.NewBOHeap(MinFirst)
+    for i:=0; i<SIZE; i++ {
+        h1.Insert(i + SIZE)
+        h2.Insert(i)
+        h3.Insert(i)
+    }
+    h2.Pop()
+
+    // h2 is copied over, and the slots it leaves behind go to the other heap of its arena.
+    h1.Merge(h2)
+    if h2.Size() != 0 {t.Errorf("merged heap not emptied")}
+    if err := h1.Validate(); err != nil {t.Fatalf("%v", err)}
+    if err := h3.Validate(); err != nil {t.Fatalf("%v", err)}
+
+    slots := len(other.nodes)
+    insert_mult_pq(h3, interval(0, SIZE))
+    if len(other.nodes) != slots {t.Errorf("slots of the copied heap were not freed, grew from %d to %d", slots, len(other.nodes))}
+
+    for i:=1; i<2*SIZE; i++ {
+        if pval := h1.Pop(); pval != i {t.Fatalf("expected %d, got %d", i, pval)}
+    }
+}
+
+
+func Test_compact_merge_order(t *testing.T) {
+    arena := NewCompactArena(0)
+    ch := arena.NewBOHeap(MinFirst)
+    max := arena.NewBOHeap(MaxFirst)
+    insert_mult_pq(max, interval(0, 10))
+
+    mustPanic(t, func() { ch.Merge(max) })
+    if ch.Size() != 0 || max.Size() != 10 {t.Errorf("merge of different orders moved keys")}
+}
+
+
+func Test_compact_order(t *testing.T) {
+    const SIZE = 300
+    rand.Seed(1)
+
+    // Both storage engines agree on the shape of a max-heap too.
+    heap := NewMaxBOHeap()
+    compact := NewCompactArena(0).NewBOHeap(MaxFirst)
+    for _, elem := range shuffle(interval(0, SIZE)) {
+        heap.Insert(elem)
+        compact.Insert(elem)
+    }
+    for i:=SIZE-1; i>=SIZE/2; i-- {
+        heap.Pop()
+        if pval := compact.Pop(); pval != i {t.Fatalf("expected %d, got %d", i, pval)}
+    }
+    if err := compact.Validate(); err != nil {t.Fatal(err)}
+    if !sameShape(heap.root, compact, compact.root) {t.Errorf("heaps have different shapes")}
+}
+
+
+func Test_compact_stable(t *testing.T) {
+    const SIZE = 1000
+    rng := rand.New(rand.NewSource(1))
+
+    for _, order := range []Order{MinFirst, MaxFirst} {
+        arena := NewCompactArena(0)
+        h1 := arena.NewStableBOHeap(order)
+        h2 := arena.NewStableBOHeap(order)
+        for i:=0; i<SIZE; i++ {
+            h1.Insert(rng.Intn(10))
+            h2.Insert(rng.Intn(10))
+        }
+        h1.Merge(h2)
+
+        var lastvalue int
+        var lastseq uint64
+        for i:=0; h1.Size() > 0; i++ {
+            seq := arena.seq(h1.root)
+            value := h1.Pop()
+            if i > 0 && value == lastvalue && seq < lastseq {t.Fatalf("key %d inserted as #%d popped after #%d", value, seq, lastseq)}
+            lastvalue, lastseq = value, seq
+        }
+    }
+}
+
+
+/*
+Both storage engines run the same algorithm, so they link the same nodes in the same order, merges included.
+ */
+func Test_compact_stats_trace(t *testing.T) {
+    rng := rand.New(rand.NewSource(1))
+
+    heap, other := NewBOHeap(), NewBOHeap()
+    arena := NewCompactArena(0)
+    compact, cother := arena.NewBOHeap(MinFirst), arena.NewBOHeap(MinFirst)
+
+    tracer, ctracer := &logTracer{}, &logTracer{}
+    heap.SetTracer(tracer)
+    compact.SetTracer(ctracer)
+    heap.EnableStats()
+    compact.EnableStats()
+
+    for i:=0; i<5000; i++ {
+        switch op := rng.Intn(10); {
+        case op == 0:
+            heap.Merge(other)
+            compact.Merge(cother)
+        case op < 4 && heap.Size() > 0:
+            if pval, cval := heap.Pop(), compact.Pop(); pval != cval {t.Fatalf("op %d: expected %d, got %d", i, pval, cval)}
+        case op < 6:
+            key := rng.Intn(1000)
+            other.Insert(key)
+            cother.Insert(key)
+        default:
+            key := rng.Intn(1000)
+            heap.Insert(key)
+            compact.Insert(key)
+        }
+    }
+
+    if err := compact.Validate(); err != nil {t.Fatal(err)}
+    if heap.Stats() != compact.Stats() {t.Errorf("expected stats %+v, got %+v", heap.Stats(), compact.Stats())}
+    if len(tracer.events) != len(ctracer.events) {t.Fatalf("expected %d events, got %d", len(tracer.events), len(ctracer.events))}
+    for i := range tracer.events {
+        if tracer.events[i] != ctracer.events[i] {t.Fatalf("event %d: expected %q, got %q", i, tracer.events[i], ctracer.events[i])}
+    }
+}
+
+
+func Test_compact_record(t *testing.T) {
+    rand.Seed(1)
+    var log bytes.Buffer
+    rec := NewRecorder(&log)
+
+    arena := NewCompactArena(0)
+    h1 := rec.NewCompactBOHeap(arena)
+    h2 := rec.NewCompactBOHeap(arena)
+    insert_mult_pq(h1, shuffle(interval(0, 100)))
+    insert_mult_pq(h2, shuffle(interval(-50, 50)))
+
+    for i:=0; i<10; i++ {
+        h1.Pop()
+    }
+    h1.Merge(h2)
+    for h1.Size() > 0 {
+        h1.Pop()
+    }
+
+    if rec.Err() != nil {t.Fatalf("recording failed: %v", rec.Err())}
+    if err := Replay(&log); err != nil {t.Errorf("replay failed: %v", err)}
+}
+
+
+func Test_compact_shrink(t *testing.T) {
+    const SIZE = 100
+
+    arena := NewCompactArena(2*SIZE)
+    h1 := arena.NewBOHeap(MinFirst)
+    h2 := arena.NewBOHeap(MinFirst)
+    insert_mult_pq(h1, interval(0, SIZE))
+    insert_mult_pq(h2, interval(0, SIZE))
+    for i:=0; i<SIZE/2; i++ {
+        h1.Pop()
+    }
+    for h2.Size() > 0 {
+        h2.Pop()
+    }
+
+    // The slots of h2 are at the end and go away, the ones h1 freed stay for reuse.
+    arena.Shrink()
+    if len(arena.nodes) != SIZE+1 || cap(arena.nodes) != SIZE+1 {t.Errorf("expected %d slots left, got %d", SIZE+1, len(arena.nodes))}
+    if err := h1.Validate(); err != nil {t.Fatal(err)}
+
+    insert_mult_pq(h2, interval(0, SIZE/2))
+    if len(arena.nodes) != SIZE+1 {t.Errorf("free slots were not reused, grew to %d", len(arena.nodes))}
+    for i:=0; i<SIZE/2; i++ {
+        if pval := h2.Pop(); pval != i {t.Fatalf("expected %d, got %d", i, pval)}
+    }
+    for i:=SIZE/2; i<SIZE; i++ {
+        if pval := h1.Pop(); pval != i {t.Fatalf("expected %d, got %d", i, pval)}
+    }
+}
+
+
+func Test_compact_merge_self(t *testing.T) {
+    ch := NewCompactBOHeap()
+    insert_mult_pq(ch, interval(0, 10))
+
+    mustPanic(t, func() { ch.Merge(ch) })
+    if ch.Size() != 10 {t.Errorf("expected size 10, got %d", ch.Size())}
+    if err := ch.Validate(); err != nil {t.Errorf("%v", err)}
+}
+
+
+func Test_compact_node_size(t *testing.T) {
+    if size := unsafe.Sizeof(cnode{}); size != 32 {t.Errorf("expected 32 byte nodes, got %d", size)}
+}
+
+
+func sameShape(bon *BONode, ch *CompactBOHeap, n int32) bool {
+    if bon == nil || n == 0 {
+        return bon == nil && n == 0
+    }
+    c := &ch.arena.nodes[n]
+    if bon.value != c.value || bon.rank != int(c.rank) {
+        return false
+    }
+    return sameShape(bon.children_head, ch, c.children) && sameShape(bon.rightsibling, ch, c.right)
+}
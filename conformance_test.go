@@ -0,0 +1,102 @@
+package BrodalOkasakiHeap_test
+
+
+import (
+    "testing"
+
+    "github.com/cngkaygusuz/BrodalOkasakiHeap"
+    "github.com/cngkaygusuz/BrodalOkasakiHeap/pqtest"
+)
+
+
+func Test_conformance_BOHeap(t *testing.T) {
+    pqtest.Run(t, func() BrodalOkasakiHeap.PriorityQueue {
+        return BrodalOkasakiHeap.NewBOHeap()
+    })
+}
+
+
+// The queue BOHeap is benchmarked against, so we know the benchmarks compare against a correct implementation.
+
+func Test_conformance_reference(t *testing.T) {
+    pqtest.Run(t, newStdQueue)
+}
+
+
+func Test_conformance_pooled_BOHeap(t *testing.T) {
+    pqtest.Run(t, func() BrodalOkasakiHeap.PriorityQueue {
+        return BrodalOkasakiHeap.NewPooledBOHeap(64, 0)
+    })
+}
+
+
+func Test_conformance_CompactBOHeap(t *testing.T) {
+    pqtest.Run(t, func() BrodalOkasakiHeap.PriorityQueue {
+        return BrodalOkasakiHeap.NewCompactBOHeap()
+    })
+}
+
+
+func Test_conformance_max_BOHeap(t *testing.T) {
+    pqtest.RunOrdered(t, func() BrodalOkasakiHeap.PriorityQueue {
+        return BrodalOkasakiHeap.NewMaxBOHeap()
+    }, func(a int, b int) bool { return a > b })
+}
+
+
+func Test_conformance_DoubleEndedBOHeap(t *testing.T) {
+    pqtest.Run(t, func() BrodalOkasakiHeap.PriorityQueue {
+        return BrodalOkasakiHeap.NewDoubleEndedBOHeap()
+    })
+}
+
+
+// With room to spare, a bounded heap must behave like any other heap.
+
+func Test_conformance_BoundedBOHeap(t *testing.T) {
+    pqtest.Run(t, func() BrodalOkasakiHeap.PriorityQueue {
+        return BrodalOkasakiHeap.NewBoundedBOHeap(1<<30, BrodalOkasakiHeap.MinFirst, BrodalOkasakiHeap.FailWhenFull)
+    })
+}
+
+
+func Test_conformance_stable_BOHeap(t *testing.T) {
+    pqtest.Run(t, func() BrodalOkasakiHeap.PriorityQueue {
+        return BrodalOkasakiHeap.NewStableBOHeap(BrodalOkasakiHeap.MinFirst)
+    })
+}
+
+
+func Test_conformance_SkewBinomialHeap(t *testing.T) {
+    pqtest.Run(t, func() BrodalOkasakiHeap.PriorityQueue {
+        return BrodalOkasakiHeap.NewSkewBinomialHeap()
+    })
+}
+
+
+func Test_conformance_BinomialHeap(t *testing.T) {
+    pqtest.Run(t, func() BrodalOkasakiHeap.PriorityQueue {
+        return BrodalOkasakiHeap.NewBinomialHeap()
+    })
+}
+
+
+func Test_conformance_PairingHeap(t *testing.T) {
+    pqtest.RunAddressable(t, func() BrodalOkasakiHeap.AddressablePriorityQueue {
+        return BrodalOkasakiHeap.NewPairingHeap()
+    })
+}
+
+
+func Test_conformance_FibonacciHeap(t *testing.T) {
+    pqtest.RunAddressable(t, func() BrodalOkasakiHeap.AddressablePriorityQueue {
+        return BrodalOkasakiHeap.NewFibonacciHeap()
+    })
+}
+
+
+func Test_conformance_StrictFibonacciHeap(t *testing.T) {
+    pqtest.RunAddressable(t, func() BrodalOkasakiHeap.AddressablePriorityQueue {
+        return BrodalOkasakiHeap.NewStrictFibonacciHeap()
+    })
+}
@@ -0,0 +1,217 @@
+/*
+Package delay is a delay queue on BOHeap: items are scheduled with a deadline, and come out once it has passed, the
+earliest deadline first.
+
+    q := delay.New[string](nil)
+    q.Schedule("hello", time.Now().Add(time.Second))
+    item, err := q.Next(ctx)  // "hello", a second later
+
+The time comes from a Clock, so tests can move it by hand instead of sleeping.
+ */
+package delay
+
+import (
+    "context"
+    "sync"
+    "time"
+
+    "github.com/cngkaygusuz/BrodalOkasakiHeap"
+)
+
+
+/*
+The source of time for a Queue.
+ */
+type Clock interface {
+    Now() time.Time
+
+    // Start a timer firing on "c" once "d" has passed. "stop" releases it, reporting false if it has already fired.
+    Timer(d time.Duration) (c <-chan time.Time, stop func() bool)
+}
+
+
+type realClock struct{}
+
+
+func (realClock) Now() time.Time {
+    return time.Now()
+}
+
+
+func (realClock) Timer(d time.Duration) (<-chan time.Time, func() bool) {
+    t := time.NewTimer(d)
+    return t.C, t.Stop
+}
+
+
+/*
+A scheduled item, the handle to cancel it with.
+ */
+type Item[T any] struct {
+    value		T
+    at			time.Time
+    entry		*BrodalOkasakiHeap.KeyedEntry[*Item[T]]
+}
+
+
+func (it *Item[T]) Value() T {
+    return it.value
+}
+
+
+func (it *Item[T]) Deadline() time.Time {
+    return it.at
+}
+
+
+/*
+A delay queue, safe for concurrent use.
+
+The items wait in a KeyedQueue on a BOHeap, keyed by BrodalOkasakiHeap.TimeKey() of their deadline. Items of equal
+deadline come out in the order they were scheduled. Deadlines before 1678 come out first, and the ones after 2262 last,
+in the order they were scheduled too. Where int is 32 bits, so are deadlines less than about 4.3 seconds apart, read
+TimeKey().
+ */
+type Queue[T any] struct {
+    mu			sync.Mutex
+    clock		Clock
+    items		*BrodalOkasakiHeap.KeyedQueue[*Item[T]]
+
+    // Closed and replaced whenever the earliest deadline changes, to wake up the waiting Next() calls.
+    changed		chan struct{}
+}
+
+
+/*
+Create a new delay queue. A nil clock means the real one.
+ */
+func New[T any](clock Clock) *Queue[T] {
+    if clock == nil {
+        clock = realClock{}
+    }
+    return &Queue[T] {
+        clock: clock,
+        items: BrodalOkasakiHeap.NewKeyedQueue[*Item[T]](nil),
+        changed: make(chan struct{}),
+    }
+}
+
+
+/*
+The amount of items waiting, due or not, not counting the cancelled ones.
+ */
+func (q *Queue[T]) Len() int {
+    q.mu.Lock()
+    defer q.mu.Unlock()
+    return q.items.Size()
+}
+
+
+/*
+Schedule "value" to come out at "at". A deadline in the past is due right away.
+ */
+func (q *Queue[T]) Schedule(value T, at time.Time) *Item[T] {
+    q.mu.Lock()
+    defer q.mu.Unlock()
+
+    it := &Item[T]{value: value, at: at}
+    key := BrodalOkasakiHeap.TimeKey(at)
+    if q.items.Size() == 0 || key < q.items.Peek().Key() {
+        q.notify()
+    }
+    it.entry = q.items.Push(key, it)
+    return it
+}
+
+
+/*
+Cancel an item that has not come out yet. Returns false if it has already come out or been cancelled.
+
+The cancelled item leaves the heap once its deadline comes up, or sooner when cancelled items come to outnumber the
+waiting ones, so timers that almost always get cancelled don't pile up.
+ */
+func (q *Queue[T]) Cancel(it *Item[T]) bool {
+    q.mu.Lock()
+    defer q.mu.Unlock()
+
+    if !q.items.Remove(it.entry) {
+        return false
+    }
+    if q.items.Size() == 0 {
+        // Nothing left to wait for, let the waiting Next() calls drop their timers.
+        q.notify()
+    }
+    return true
+}
+
+
+/*
+Pop the earliest item if its deadline has passed. ok is false if there is none due.
+ */
+func (q *Queue[T]) Poll() (value T, ok bool) {
+    q.mu.Lock()
+    defer q.mu.Unlock()
+
+    it := q.first()
+    if it == nil || it.at.After(q.clock.Now()) {
+        return value, false
+    }
+    q.items.Pop()
+    return it.value, true
+}
+
+
+/*
+Pop the earliest item, blocking until its deadline passes. Returns the error of "ctx" if it is done first.
+ */
+func (q *Queue[T]) Next(ctx context.Context) (value T, err error) {
+    for {
+        q.mu.Lock()
+        it := q.first()
+        changed := q.changed
+
+        var timer <-chan time.Time
+        var stop func() bool
+        if it != nil {
+            wait := it.at.Sub(q.clock.Now())
+            if wait <= 0 {
+                q.items.Pop()
+                q.mu.Unlock()
+                return it.value, nil
+            }
+            timer, stop = q.clock.Timer(wait)
+        }
+        q.mu.Unlock()
+
+        // A nil timer blocks forever, until something is scheduled.
+        select {
+        case <-ctx.Done():
+            if stop != nil {
+                stop()
+            }
+            return value, ctx.Err()
+        case <-changed:
+            if stop != nil {
+                stop()
+            }
+        case <-timer:
+        }
+    }
+}
+
+
+/*
+Return the earliest item, nil if there is none.
+ */
+func (q *Queue[T]) first() *Item[T] {
+    if q.items.Size() == 0 {
+        return nil
+    }
+    return q.items.Peek().Value()
+}
+
+
+func (q *Queue[T]) notify() {
+    close(q.changed)
+    q.changed = make(chan struct{})
+}
@@ -0,0 +1,295 @@
+package delay
+
+
+import (
+    "context"
+    "errors"
+    "runtime"
+    "sync"
+    "testing"
+    "time"
+)
+
+
+/*
+A clock that only moves when told to.
+ */
+type manualClock struct {
+    mu		sync.Mutex
+    cond	*sync.Cond
+    now		time.Time
+    timers	[]*manualTimer
+}
+
+
+type manualTimer struct {
+    at		time.Time
+    c		chan time.Time
+}
+
+
+func newManualClock() *manualClock {
+    mc := &manualClock{now: time.Unix(1000, 0)}
+    mc.cond = sync.NewCond(&mc.mu)
+    return mc
+}
+
+
+func (mc *manualClock) Now() time.Time {
+    mc.mu.Lock()
+    defer mc.mu.Unlock()
+    return mc.now
+}
+
+
+func (mc *manualClock) Timer(d time.Duration) (<-chan time.Time, func() bool) {
+    mc.mu.Lock()
+    defer mc.mu.Unlock()
+
+    timer := &manualTimer{at: mc.now.Add(d), c: make(chan time.Time, 1)}
+    mc.timers = append(mc.timers, timer)
+    mc.cond.Broadcast()
+    return timer.c, func() bool { return mc.stop(timer) }
+}
+
+
+func (mc *manualClock) stop(timer *manualTimer) bool {
+    mc.mu.Lock()
+    defer mc.mu.Unlock()
+
+    for i, other := range mc.timers {
+        if other == timer {
+            mc.timers = append(mc.timers[:i], mc.timers[i+1:]...)
+            mc.cond.Broadcast()
+            return true
+        }
+    }
+    return false
+}
+
+
+/*
+Move the clock forward, firing the timers that are due.
+ */
+func (mc *manualClock) Advance(d time.Duration) {
+    mc.mu.Lock()
+    defer mc.mu.Unlock()
+
+    mc.now = mc.now.Add(d)
+    var waiting []*manualTimer
+    for _, timer := range mc.timers {
+        if timer.at.After(mc.now) {
+            waiting = append(waiting, timer)
+        } else {
+            timer.c <- mc.now
+        }
+    }
+    mc.timers = waiting
+    mc.cond.Broadcast()
+}
+
+
+/*
+Block until there is a timer waiting for "at", so that advancing the clock past it wakes its owner.
+ */
+func (mc *manualClock) waitTimer(at time.Time) {
+    mc.mu.Lock()
+    defer mc.mu.Unlock()
+
+    for {
+        for _, timer := range mc.timers {
+            if timer.at.Equal(at) {
+                return
+            }
+        }
+        mc.cond.Wait()
+    }
+}
+
+
+type result struct {
+    value	string
+    err		error
+}
+
+
+func next(ctx context.Context, q *Queue[string]) <-chan result {
+    c := make(chan result, 1)
+    go func() {
+        value, err := q.Next(ctx)
+        c <- result{value, err}
+    }()
+    return c
+}
+
+
+func Test_delay_poll(t *testing.T) {
+    clock := newManualClock()
+    q := New[string](clock)
+    start := clock.Now()
+
+    q.Schedule("b", start.Add(2*time.Second))
+    q.Schedule("a", start.Add(time.Second))
+    q.Schedule("c", start.Add(2*time.Second))
+    q.Schedule("past", start.Add(-time.Second))
+    if q.Len() != 4 {t.Errorf("expected 4 items, got %d", q.Len())}
+
+    if value, ok := q.Poll(); !ok || value != "past" {t.Errorf("expected the past item to be due, got %q, %v", value, ok)}
+    if _, ok := q.Poll(); ok {t.Errorf("popped an item before its deadline")}
+
+    clock.Advance(time.Second)
+    if value, ok := q.Poll(); !ok || value != "a" {t.Errorf("expected a, got %q, %v", value, ok)}
+
+    clock.Advance(5*time.Second)
+    for _, expected := range []string{"b", "c"} {
+        if value, ok := q.Poll(); !ok || value != expected {t.Errorf("expected %s, got %q, %v", expected, value, ok)}
+    }
+    if q.Len() != 0 {t.Errorf("expected an empty queue, got %d", q.Len())}
+}
+
+
+func Test_delay_cancel(t *testing.T) {
+    clock := newManualClock()
+    q := New[string](clock)
+    start := clock.Now()
+
+    a := q.Schedule("a", start)
+    q.Schedule("b", start)
+    if !q.Cancel(a) {t.Errorf("cancelling a waiting item failed")}
+    if q.Cancel(a) {t.Errorf("cancelled an item twice")}
+    if q.Len() != 1 {t.Errorf("expected 1 item, got %d", q.Len())}
+
+    value, ok := q.Poll()
+    if !ok || value != "b" {t.Errorf("expected b, got %q, %v", value, ok)}
+
+    c := q.Schedule("c", start)
+    q.Poll()
+    if q.Cancel(c) {t.Errorf("cancelled an item that came out")}
+}
+
+
+func Test_delay_next_blocks(t *testing.T) {
+    clock := newManualClock()
+    q := New[string](clock)
+    at := clock.Now().Add(time.Minute)
+    q.Schedule("a", at)
+
+    c := next(context.Background(), q)
+    clock.waitTimer(at)
+    select {
+    case r := <-c:
+        t.Fatalf("Next() returned %v before the deadline", r)
+    default:
+    }
+
+    clock.Advance(time.Minute)
+    if r := <-c; r.err != nil || r.value != "a" {t.Errorf("expected a, got %v", r)}
+}
+
+
+func Test_delay_next_earlier(t *testing.T) {
+    clock := newManualClock()
+    q := New[string](clock)
+    start := clock.Now()
+
+    c := next(context.Background(), q)
+    q.Schedule("late", start.Add(time.Hour))
+    clock.waitTimer(start.Add(time.Hour))
+
+    // An earlier deadline cuts the wait short.
+    q.Schedule("early", start.Add(time.Second))
+    clock.waitTimer(start.Add(time.Second))
+    clock.Advance(time.Second)
+    if r := <-c; r.err != nil || r.value != "early" {t.Errorf("expected early, got %v", r)}
+}
+
+
+func Test_delay_next_cancelled(t *testing.T) {
+    clock := newManualClock()
+    q := New[string](clock)
+    start := clock.Now()
+
+    a := q.Schedule("a", start.Add(time.Second))
+    q.Schedule("b", start.Add(2*time.Second))
+
+    c := next(context.Background(), q)
+    clock.waitTimer(start.Add(time.Second))
+    q.Cancel(a)
+
+    clock.Advance(time.Second)
+    clock.waitTimer(start.Add(2*time.Second))
+    clock.Advance(time.Second)
+    if r := <-c; r.err != nil || r.value != "b" {t.Errorf("expected b, got %v", r)}
+}
+
+
+func Test_delay_next_context(t *testing.T) {
+    clock := newManualClock()
+    q := New[string](clock)
+    at := clock.Now().Add(time.Minute)
+    q.Schedule("a", at)
+
+    ctx, cancel := context.WithCancel(context.Background())
+    c := next(ctx, q)
+    clock.waitTimer(at)
+    cancel()
+
+    if r := <-c; !errors.Is(r.err, context.Canceled) {t.Errorf("expected context.Canceled, got %v", r)}
+    if q.Len() != 1 {t.Errorf("cancelled Next() took the item")}
+}
+
+
+func Test_delay_out_of_range(t *testing.T) {
+    clock := newManualClock()
+    q := New[string](clock)
+    start := clock.Now()
+
+    // Unix nanoseconds of these don't fit in an int.
+    never := start.AddDate(300, 0, 0)
+    q.Schedule("never", never)
+    q.Schedule("zero", time.Time{})
+    q.Schedule("due", start.Add(-time.Second))
+
+    if value, ok := q.Poll(); !ok || value != "zero" {t.Fatalf("expected zero, got %q, %v", value, ok)}
+    if r := <-next(context.Background(), q); r.err != nil || r.value != "due" {t.Fatalf("expected due, got %v", r)}
+    if value, ok := q.Poll(); ok {t.Fatalf("popped %q centuries early", value)}
+
+    // The wait is longer than a time.Duration can hold, so Next() settles for the longest one.
+    ctx, cancel := context.WithCancel(context.Background())
+    c := next(ctx, q)
+    clock.waitTimer(start.Add(never.Sub(start)))
+    cancel()
+    if r := <-c; !errors.Is(r.err, context.Canceled) {t.Errorf("expected context.Canceled, got %v", r)}
+    if q.Len() != 1 {t.Errorf("expected never to be waiting, got %d items", q.Len())}
+}
+
+
+func Test_delay_cancelled_timers(t *testing.T) {
+    const TIMERS = 200000
+    clock := newManualClock()
+    q := New[int](clock)
+    start := clock.Now()
+
+    // Timeouts an hour away, each cancelled right after the next one is set, behind an item that is due sooner.
+    q.Schedule(-1, start.Add(time.Minute))
+    var before, after runtime.MemStats
+    runtime.GC()
+    runtime.ReadMemStats(&before)
+
+    var timer *Item[int]
+    for i:=0; i<TIMERS; i++ {
+        next := q.Schedule(i, start.Add(time.Hour + time.Duration(i)))
+        if timer != nil && !q.Cancel(timer) {t.Fatalf("cancelling timer %d failed", i-1)}
+        timer = next
+    }
+
+    runtime.GC()
+    runtime.ReadMemStats(&after)
+    if q.Len() != 2 {t.Fatalf("expected the last timer waiting, got %d items", q.Len())}
+    if grown := int64(after.HeapAlloc) - int64(before.HeapAlloc); grown > 4 << 20 {t.Errorf("cancelled timers kept around, heap grew by %d bytes", grown)}
+
+    clock.Advance(2*time.Hour)
+    q.Poll()
+    if value, ok := q.Poll(); !ok || value != TIMERS-1 {t.Errorf("expected the last timer, got %d, %v", value, ok)}
+    if _, ok := q.Poll(); ok {t.Errorf("a cancelled timer came out")}
+}
@@ -0,0 +1,176 @@
+package BrodalOkasakiHeap
+
+
+/*
+A double-ended priority queue: both the smallest and the largest key can be popped.
+
+It is made of two heaps holding the same keys, a min-heap and a max-heap. Popping from one side leaves the key behind in
+the other heap, so we write it down as "gone" there instead of digging it out, which a Brodal-Okasaki heap can't do
+anyway. A gone key is thrown away once it shows up at the top of its heap. Equal keys can't be told apart, so counting
+them is enough.
+
+Every key is held twice, and gone keys linger until they reach the top or get compacted away, read compact(). At most
+as many gone keys as live ones are kept in each heap, so this takes at most four times the memory of a single heap.
+Insert() is O(1), popping is amortized O(logn).
+ */
+type DoubleEndedBOHeap struct {
+    min		*BOHeap
+    max		*BOHeap
+
+    gonemin	map[int]int		// Keys popped by PopMax() that are still in "min", with their counts.
+    gonemax	map[int]int		// Keys popped by PopMin() that are still in "max", with their counts.
+
+    size	int
+}
+
+
+/*
+Create a new double-ended priority queue.
+ */
+func NewDoubleEndedBOHeap() *DoubleEndedBOHeap {
+    return &DoubleEndedBOHeap {
+        min: NewBOHeap(),
+        max: NewMaxBOHeap(),
+        gonemin: make(map[int]int),
+        gonemax: make(map[int]int),
+    }
+}
+
+
+func (dq *DoubleEndedBOHeap) Insert(value int) {
+    dq.min.Insert(value)
+    dq.max.Insert(value)
+    dq.size += 1
+}
+
+
+/*
+Return the number of keys in the queue, not counting the ones that are gone.
+ */
+func (dq *DoubleEndedBOHeap) Size() int {
+    return dq.size
+}
+
+
+func (dq *DoubleEndedBOHeap) PeekMin() int {
+    if dq.size == 0 {
+        panic("DoubleEndedBOHeap: PeekMin() on an empty queue")
+    }
+    discardGone(dq.min, dq.gonemin)
+    return dq.min.Peek()
+}
+
+
+func (dq *DoubleEndedBOHeap) PeekMax() int {
+    if dq.size == 0 {
+        panic("DoubleEndedBOHeap: PeekMax() on an empty queue")
+    }
+    discardGone(dq.max, dq.gonemax)
+    return dq.max.Peek()
+}
+
+
+func (dq *DoubleEndedBOHeap) PopMin() int {
+    if dq.size == 0 {
+        panic("DoubleEndedBOHeap: PopMin() on an empty queue")
+    }
+    discardGone(dq.min, dq.gonemin)
+
+    value := dq.min.Pop()
+    dq.gonemax[value] += 1
+    dq.size -= 1
+    dq.compact()
+    return value
+}
+
+
+func (dq *DoubleEndedBOHeap) PopMax() int {
+    if dq.size == 0 {
+        panic("DoubleEndedBOHeap: PopMax() on an empty queue")
+    }
+    discardGone(dq.max, dq.gonemax)
+
+    value := dq.max.Pop()
+    dq.gonemin[value] += 1
+    dq.size -= 1
+    dq.compact()
+    return value
+}
+
+
+/*
+Pop() and Peek() work on the minimum, so the queue can be used wherever a PriorityQueue is expected.
+ */
+func (dq *DoubleEndedBOHeap) Pop() int {
+    return dq.PopMin()
+}
+
+
+func (dq *DoubleEndedBOHeap) Peek() int {
+    return dq.PeekMin()
+}
+
+
+/*
+Merge both heaps and the gone keys of "other", which must be a *DoubleEndedBOHeap too. "other" is left empty.
+ */
+func (dq *DoubleEndedBOHeap) Merge(pq PriorityQueue) {
+    other, ok := pq.(*DoubleEndedBOHeap)
+    if !ok {
+        panic("DoubleEndedBOHeap: can only merge with another *DoubleEndedBOHeap")
+    }
+
+    dq.min.Merge(other.min)
+    dq.max.Merge(other.max)
+    for value, count := range other.gonemin {
+        dq.gonemin[value] += count
+    }
+    for value, count := range other.gonemax {
+        dq.gonemax[value] += count
+    }
+    dq.size += other.size
+
+    *other = *NewDoubleEndedBOHeap()
+}
+
+
+/*
+Gone keys only surface when popping from their side, which may never happen: a queue that is only ever popped from one
+end would keep every key it ever held in the other heap. So once either heap holds more gone keys than live ones, the
+live keys are moved over to new heaps and the old ones are dropped along with everything gone.
+
+That takes O(nlogn) time, paid for by the more than n pops since the last time, so popping stays amortized O(logn).
+ */
+func (dq *DoubleEndedBOHeap) compact() {
+    if dq.min.Size() <= 2*dq.size && dq.max.Size() <= 2*dq.size {
+        return
+    }
+
+    fresh := NewDoubleEndedBOHeap()
+    for i:=0; i<dq.size; i++ {
+        discardGone(dq.min, dq.gonemin)
+        fresh.Insert(dq.min.Pop())
+    }
+    *dq = *fresh
+}
+
+
+/*
+Throw away the keys at the top of "bq" that are gone.
+ */
+func discardGone(bq *BOHeap, gone map[int]int) {
+    for len(gone) > 0 {
+        value := bq.Peek()
+        count := gone[value]
+        if count == 0 {
+            return
+        }
+
+        bq.Pop()
+        if count == 1 {
+            delete(gone, value)
+        } else {
+            gone[value] = count - 1
+        }
+    }
+}
@@ -0,0 +1,111 @@
+package BrodalOkasakiHeap
+
+
+import (
+    "testing"
+    "math/rand"
+    "sort"
+)
+
+
+func Test_depq_both_ends(t *testing.T) {
+    const SIZE = 100
+    rand.Seed(1)
+
+    dq := NewDoubleEndedBOHeap()
+    insert_mult_pq(dq, shuffle(interval(0, SIZE)))
+
+    // Eat the queue from both ends towards the middle.
+    for i:=0; i<SIZE/2; i++ {
+        if dq.PeekMin() != i {t.Fatalf("expected min %d, got %d", i, dq.PeekMin())}
+        if dq.PeekMax() != SIZE-1-i {t.Fatalf("expected max %d, got %d", SIZE-1-i, dq.PeekMax())}
+        if pval := dq.PopMin(); pval != i {t.Fatalf("expected %d, got %d", i, pval)}
+        if pval := dq.PopMax(); pval != SIZE-1-i {t.Fatalf("expected %d, got %d", SIZE-1-i, pval)}
+    }
+    if dq.Size() != 0 {t.Errorf("expected an empty queue, got size %d", dq.Size())}
+}
+
+
+func Test_depq_random(t *testing.T) {
+    rng := rand.New(rand.NewSource(1))
+    dq := NewDoubleEndedBOHeap()
+    var model []int
+
+    for i:=0; i<5000; i++ {
+        switch op := rng.Intn(4); {
+        case op < 2 || len(model) == 0:
+            value := rng.Intn(50)
+            dq.Insert(value)
+            model = append(model, value)
+            sort.Ints(model)
+        case op == 2:
+            if pval := dq.PopMin(); pval != model[0] {t.Fatalf("op %d: expected min %d, got %d", i, model[0], pval)}
+            model = model[1:]
+        default:
+            if pval := dq.PopMax(); pval != model[len(model)-1] {t.Fatalf("op %d: expected max %d, got %d", i, model[len(model)-1], pval)}
+            model = model[:len(model)-1]
+        }
+        if dq.Size() != len(model) {t.Fatalf("op %d: expected size %d, got %d", i, len(model), dq.Size())}
+    }
+}
+
+
+func Test_depq_merge(t *testing.T) {
+    d1 := NewDoubleEndedBOHeap()
+    d2 := NewDoubleEndedBOHeap()
+    insert_mult_pq(d1, interval(0, 10))
+    insert_mult_pq(d2, interval(10, 20))
+
+    // Leave gone keys behind in both before merging.
+    d1.PopMax()
+    d2.PopMin()
+
+    d1.Merge(d2)
+    if d1.Size() != 18 {t.Errorf("expected size 18, got %d", d1.Size())}
+    if d2.Size() != 0 {t.Errorf("merged queue not emptied")}
+
+    expected := append(interval(0, 9), interval(11, 20)...)
+    for _, value := range expected {
+        if pval := d1.PopMin(); pval != value {t.Fatalf("expected %d, got %d", value, pval)}
+    }
+}
+
+
+func Test_depq_empty_reset(t *testing.T) {
+    dq := NewDoubleEndedBOHeap()
+    insert_mult_pq(dq, []int{1, 2, 3})
+    dq.PopMin()
+    dq.PopMax()
+    dq.PopMax()
+
+    if dq.min.Size() != 0 || dq.max.Size() != 0 || len(dq.gonemin) != 0 || len(dq.gonemax) != 0 {t.Errorf("gone keys kept around in an empty queue")}
+}
+
+
+func Test_depq_one_sided_bounded(t *testing.T) {
+    rng := rand.New(rand.NewSource(1))
+
+    // Popping from one end only must not pile up gone keys in the heap of the other end.
+    for _, popMax := range []bool{false, true} {
+        dq := NewDoubleEndedBOHeap()
+        insert_mult_pq(dq, interval(0, 10))
+
+        for i:=0; i<100000; i++ {
+            dq.Insert(rng.Intn(1000))
+            if popMax {
+                dq.PopMax()
+            } else {
+                dq.PopMin()
+            }
+            if dq.min.Size() > 20 || dq.max.Size() > 20 {t.Fatalf("op %d: heaps of %d and %d nodes for 10 keys", i, dq.min.Size(), dq.max.Size())}
+            if len(dq.gonemin) > 10 || len(dq.gonemax) > 10 {t.Fatalf("op %d: %d and %d gone keys for 10 keys", i, len(dq.gonemin), len(dq.gonemax))}
+        }
+    }
+}
+
+
+func insert_mult_pq(pq PriorityQueue, values []int) {
+    for _, elem := range values {
+        pq.Insert(elem)
+    }
+}
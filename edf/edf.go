@@ -0,0 +1,255 @@
+/*
+Package edf is an earliest-deadline-first scheduler on BOHeap: submitted tasks wait in a heap keyed by deadline, and a
+pool of workers always runs the waiting task whose deadline is the earliest.
+
+    s := edf.New(edf.Config{Workers: 4, OnMiss: func(task *edf.Task, lateness time.Duration) {
+        log.Printf("%s finished %v late", task.Name, lateness)
+    }})
+    s.Submit(&edf.Task{Name: "report", Deadline: time.Now().Add(time.Minute), Run: makeReport})
+    s.Close()
+
+A task misses its deadline when it finishes after it. Misses are reported to a callback and summed up in Metrics.
+ */
+package edf
+
+import (
+    "errors"
+    "sync"
+    "time"
+
+    "github.com/cngkaygusuz/BrodalOkasakiHeap"
+)
+
+
+var ErrClosed = errors.New("edf: scheduler is closed")
+
+
+type Task struct {
+    Name		string
+    Deadline	time.Time
+    Duration	time.Duration		// The expected run time, read Config.Firm.
+    Run			func()
+}
+
+
+/*
+The source of time of a Scheduler, for measuring lateness.
+ */
+type Clock interface {
+    Now() time.Time
+}
+
+
+type realClock struct{}
+
+
+func (realClock) Now() time.Time {
+    return time.Now()
+}
+
+
+type Config struct {
+    // The amount of workers running tasks, 1 if not set.
+    Workers		int
+
+    // The clock to measure lateness with, the real one if nil.
+    Clock		Clock
+
+    // Called by the worker whenever a task misses its deadline, with how late it is. Must be safe for concurrent use
+    // when there are several workers.
+    OnMiss		func(task *Task, lateness time.Duration)
+
+    // Firm deadlines: a task that can't finish in time, its Duration counted from when a worker picks it up, is not
+    // run at all but reported as missed right away, with the lateness it would have had.
+    Firm		bool
+}
+
+
+/*
+A snapshot of the scheduler.
+ */
+type Metrics struct {
+    Depth			int					// Tasks waiting for a worker.
+    Running			int					// Tasks being run.
+    Completed		int					// Tasks that have run, late or not.
+    Missed			int					// Tasks that missed their deadline, run or skipped.
+    Skipped			int					// Tasks not run due to Config.Firm.
+    TotalLateness	time.Duration		// Sum of the lateness of the missed tasks.
+    MaxLateness		time.Duration
+}
+
+
+/*
+The average lateness of the missed tasks, 0 if none missed.
+ */
+func (m Metrics) MeanLateness() time.Duration {
+    if m.Missed == 0 {
+        return 0
+    }
+    return m.TotalLateness / time.Duration(m.Missed)
+}
+
+
+/*
+An EDF scheduler, safe for concurrent use.
+
+The waiting tasks are in a KeyedQueue on a BOHeap, keyed by BrodalOkasakiHeap.TimeKey() of their deadline. Tasks of
+equal deadline run in the order they were submitted. Deadlines before 1678, the zero time.Time included, run first, and
+the ones after 2262 last, in the order they were submitted too. Where int is 32 bits, so do deadlines less than about
+4.3 seconds apart, read TimeKey().
+ */
+type Scheduler struct {
+    mu			sync.Mutex
+    ready		*sync.Cond			// Signalled when a task is submitted or the scheduler closes.
+    config		Config
+    tasks		*BrodalOkasakiHeap.KeyedQueue[*Task]
+    metrics		Metrics
+    closed		bool
+    workers		sync.WaitGroup
+}
+
+
+/*
+Create a scheduler and start its workers.
+ */
+func New(config Config) *Scheduler {
+    if config.Workers <= 0 {
+        config.Workers = 1
+    }
+    if config.Clock == nil {
+        config.Clock = realClock{}
+    }
+
+    s := &Scheduler {
+        config: config,
+        tasks: BrodalOkasakiHeap.NewKeyedQueue[*Task](nil),
+    }
+    s.ready = sync.NewCond(&s.mu)
+
+    s.workers.Add(config.Workers)
+    for i:=0; i<config.Workers; i++ {
+        go s.work()
+    }
+    return s
+}
+
+
+/*
+Queue a task to be run. Panics if it has no Run function, returns ErrClosed once the scheduler is closed.
+ */
+func (s *Scheduler) Submit(task *Task) error {
+    if task.Run == nil {
+        panic("edf: task without a Run function")
+    }
+
+    s.mu.Lock()
+    defer s.mu.Unlock()
+
+    if s.closed {
+        return ErrClosed
+    }
+    s.tasks.Push(BrodalOkasakiHeap.TimeKey(task.Deadline), task)
+    s.metrics.Depth += 1
+    s.ready.Signal()
+    return nil
+}
+
+
+/*
+The amount of tasks waiting for a worker.
+ */
+func (s *Scheduler) Depth() int {
+    s.mu.Lock()
+    defer s.mu.Unlock()
+    return s.metrics.Depth
+}
+
+
+func (s *Scheduler) Metrics() Metrics {
+    s.mu.Lock()
+    defer s.mu.Unlock()
+    return s.metrics
+}
+
+
+/*
+Stop accepting tasks, and wait for the workers to run the ones already waiting.
+ */
+func (s *Scheduler) Close() {
+    s.mu.Lock()
+    s.closed = true
+    s.ready.Broadcast()
+    s.mu.Unlock()
+
+    s.workers.Wait()
+}
+
+
+func (s *Scheduler) work() {
+    defer s.workers.Done()
+
+    for {
+        task := s.take()
+        if task == nil {
+            return
+        }
+
+        if s.config.Firm {
+            if lateness := s.config.Clock.Now().Add(task.Duration).Sub(task.Deadline); lateness > 0 {
+                s.finish(task, lateness, true)
+                continue
+            }
+        }
+
+        task.Run()
+        s.finish(task, s.config.Clock.Now().Sub(task.Deadline), false)
+    }
+}
+
+
+/*
+Wait for a task and take the one with the earliest deadline. nil once the scheduler is closed and drained.
+ */
+func (s *Scheduler) take() *Task {
+    s.mu.Lock()
+    defer s.mu.Unlock()
+
+    for s.tasks.Size() == 0 {
+        if s.closed {
+            return nil
+        }
+        s.ready.Wait()
+    }
+
+    task := s.tasks.Pop().Value()
+    s.metrics.Depth -= 1
+    s.metrics.Running += 1
+    return task
+}
+
+
+/*
+Account for a task that is done, reporting it if it is late.
+ */
+func (s *Scheduler) finish(task *Task, lateness time.Duration, skipped bool) {
+    s.mu.Lock()
+    m := &s.metrics
+    m.Running -= 1
+    if skipped {
+        m.Skipped += 1
+    } else {
+        m.Completed += 1
+    }
+    if lateness > 0 {
+        m.Missed += 1
+        m.TotalLateness += lateness
+        if lateness > m.MaxLateness {
+            m.MaxLateness = lateness
+        }
+    }
+    s.mu.Unlock()
+
+    if lateness > 0 && s.config.OnMiss != nil {
+        s.config.OnMiss(task, lateness)
+    }
+}
@@ -0,0 +1,227 @@
+package edf
+
+
+import (
+    "math/rand"
+    "sync"
+    "testing"
+    "time"
+)
+
+
+/*
+A clock moved by the tasks themselves, as if every task took its Duration to run.
+ */
+type manualClock struct {
+    mu		sync.Mutex
+    now		time.Time
+}
+
+
+func (mc *manualClock) Now() time.Time {
+    mc.mu.Lock()
+    defer mc.mu.Unlock()
+    return mc.now
+}
+
+
+func (mc *manualClock) Advance(d time.Duration) {
+    mc.mu.Lock()
+    defer mc.mu.Unlock()
+    mc.now = mc.now.Add(d)
+}
+
+
+/*
+Hold the only worker of "s" until the returned function is called, so that tasks pile up behind it.
+ */
+func block(t *testing.T, s *Scheduler, deadline time.Time) func() {
+    started := make(chan struct{})
+    release := make(chan struct{})
+    err := s.Submit(&Task{Name: "block", Deadline: deadline, Run: func() {
+        close(started)
+        <-release
+    }})
+    if err != nil {t.Fatalf("unexpected error %v", err)}
+    <-started
+    return func() { close(release) }
+}
+
+
+func Test_edf_order(t *testing.T) {
+    clock := &manualClock{now: time.Unix(1000, 0)}
+    s := New(Config{Clock: clock})
+    release := block(t, s, clock.Now())
+
+    var order []int
+    rng := rand.New(rand.NewSource(1))
+    for _, i := range rng.Perm(50) {
+        i := i
+        s.Submit(&Task{Deadline: clock.Now().Add(time.Duration(i/2) * time.Second), Run: func() { order = append(order, i) }})
+    }
+    if s.Depth() != 50 {t.Errorf("expected depth 50, got %d", s.Depth())}
+    if m := s.Metrics(); m.Running != 1 {t.Errorf("expected 1 running task, got %d", m.Running)}
+
+    release()
+    s.Close()
+    if len(order) != 50 {t.Fatalf("expected 50 tasks run, got %d", len(order))}
+    for i:=1; i<len(order); i++ {
+        if order[i]/2 < order[i-1]/2 {t.Fatalf("tasks not in deadline order: %v", order)}
+    }
+}
+
+
+func Test_edf_ties(t *testing.T) {
+    clock := &manualClock{now: time.Unix(1000, 0)}
+    s := New(Config{Clock: clock})
+    release := block(t, s, clock.Now())
+
+    var order []int
+    deadline := clock.Now().Add(time.Second)
+    for i:=0; i<10; i++ {
+        i := i
+        s.Submit(&Task{Deadline: deadline, Run: func() { order = append(order, i) }})
+    }
+    release()
+    s.Close()
+
+    for i := range order {
+        if order[i] != i {t.Fatalf("equal deadlines not run in submission order: %v", order)}
+    }
+}
+
+
+func Test_edf_out_of_range(t *testing.T) {
+    clock := &manualClock{now: time.Unix(1000, 0)}
+    start := clock.Now()
+    s := New(Config{Clock: clock})
+    release := block(t, s, start)
+
+    // A far away "never" must not wrap around and run before the urgent tasks.
+    var order []string
+    for _, task := range []*Task{
+        {Name: "never", Deadline: start.AddDate(300, 0, 0)},
+        {Name: "urgent", Deadline: start.Add(time.Second)},
+        {Name: "overdue", Deadline: time.Time{}},
+        {Name: "later", Deadline: start.AddDate(200, 0, 0)},
+    } {
+        task := task
+        task.Run = func() { order = append(order, task.Name) }
+        s.Submit(task)
+    }
+    release()
+    s.Close()
+
+    expected := []string{"overdue", "urgent", "later", "never"}
+    if len(order) != len(expected) {t.Fatalf("expected %v, got %v", expected, order)}
+    for i := range expected {
+        if order[i] != expected[i] {t.Fatalf("expected %v, got %v", expected, order)}
+    }
+}
+
+
+func Test_edf_lateness(t *testing.T) {
+    clock := &manualClock{now: time.Unix(1000, 0)}
+    start := clock.Now()
+
+    var missed []string
+    var lateness []time.Duration
+    s := New(Config{Clock: clock, OnMiss: func(task *Task, late time.Duration) {
+        missed = append(missed, task.Name)
+        lateness = append(lateness, late)
+    }})
+    release := block(t, s, start)
+
+    // Run back to back from "start": a finishes at 2s, b at 5s, c at 6s.
+    for _, task := range []*Task{
+        {Name: "c", Deadline: start.Add(4*time.Second), Duration: time.Second},
+        {Name: "a", Deadline: start.Add(2*time.Second), Duration: 2*time.Second},
+        {Name: "b", Deadline: start.Add(3*time.Second), Duration: 3*time.Second},
+    } {
+        task := task
+        task.Run = func() { clock.Advance(task.Duration) }
+        s.Submit(task)
+    }
+    release()
+    s.Close()
+
+    if len(missed) != 2 || missed[0] != "b" || missed[1] != "c" {t.Fatalf("expected b and c to miss, got %v", missed)}
+    if lateness[0] != 2*time.Second || lateness[1] != 2*time.Second {t.Errorf("expected 2s late each, got %v", lateness)}
+
+    m := s.Metrics()
+    if m.Completed != 4 || m.Missed != 2 || m.Skipped != 0 {t.Errorf("unexpected counts %+v", m)}
+    if m.TotalLateness != 4*time.Second || m.MaxLateness != 2*time.Second || m.MeanLateness() != 2*time.Second {t.Errorf("unexpected lateness %+v", m)}
+    if m.Depth != 0 || m.Running != 0 {t.Errorf("expected an idle scheduler, got %+v", m)}
+}
+
+
+func Test_edf_firm(t *testing.T) {
+    clock := &manualClock{now: time.Unix(1000, 0)}
+    start := clock.Now()
+
+    var missed []string
+    s := New(Config{Clock: clock, Firm: true, OnMiss: func(task *Task, late time.Duration) {
+        missed = append(missed, task.Name)
+        if late != time.Second {t.Errorf("expected b to be 1s late, got %v", late)}
+    }})
+    release := block(t, s, start)
+
+    // a takes up to 2s, so b can't finish by 3s anymore and is skipped, but c still makes it.
+    var ran []string
+    for _, task := range []*Task{
+        {Name: "a", Deadline: start.Add(2*time.Second), Duration: 2*time.Second},
+        {Name: "b", Deadline: start.Add(3*time.Second), Duration: 2*time.Second},
+        {Name: "c", Deadline: start.Add(4*time.Second), Duration: 2*time.Second},
+    } {
+        task := task
+        task.Run = func() {
+            ran = append(ran, task.Name)
+            clock.Advance(task.Duration)
+        }
+        s.Submit(task)
+    }
+    release()
+    s.Close()
+
+    if len(ran) != 2 || ran[0] != "a" || ran[1] != "c" {t.Errorf("expected a and c to run, got %v", ran)}
+    if len(missed) != 1 || missed[0] != "b" {t.Errorf("expected b to miss, got %v", missed)}
+    if m := s.Metrics(); m.Skipped != 1 || m.Missed != 1 || m.Completed != 3 {t.Errorf("unexpected counts %+v", m)}
+}
+
+
+func Test_edf_pool(t *testing.T) {
+    const (
+        WORKERS = 4
+        TASKS = 1000
+    )
+    s := New(Config{Workers: WORKERS})
+
+    var mu sync.Mutex
+    count := 0
+    deadline := time.Now().Add(time.Hour)
+    for i:=0; i<TASKS; i++ {
+        s.Submit(&Task{Deadline: deadline.Add(time.Duration(i % 7) * time.Second), Run: func() {
+            mu.Lock()
+            count += 1
+            mu.Unlock()
+        }})
+    }
+    s.Close()
+
+    if count != TASKS {t.Errorf("expected %d tasks run, got %d", TASKS, count)}
+    if m := s.Metrics(); m.Completed != TASKS || m.Missed != 0 {t.Errorf("unexpected counts %+v", m)}
+}
+
+
+func Test_edf_closed(t *testing.T) {
+    s := New(Config{})
+    s.Close()
+    if err := s.Submit(&Task{Run: func() {}}); err != ErrClosed {t.Errorf("expected ErrClosed, got %v", err)}
+
+    s = New(Config{})
+    defer s.Close()
+    defer func() {
+        if recover() == nil {t.Errorf("task without Run did not panic")}
+    }()
+    s.Submit(&Task{})
+}
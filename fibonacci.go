@@ -0,0 +1,309 @@
+package BrodalOkasakiHeap
+
+
+/*
+A Fibonacci heap: a lazy forest of heap ordered trees, tidied up only by Pop().
+
+    * Insert(), Merge() and Peek() take O(1) time, Insert() and Merge() just splice root lists.
+    * Pop() links the trees of the same degree until the degrees are distinct. O(logn) amortized.
+    * DecreaseKey() cuts the node off its parent, and cuts the parent as well if it is marked, i.e. lost a child
+      before. O(1) amortized.
+ */
+type FibonacciHeap struct {
+    min		*fibNode	// Points into the circular root list.
+    size	int
+}
+
+
+/*
+Siblings, including the roots, form a circular doubly linked list through "left" and "right".
+ */
+type fibNode struct {
+    key		int
+    degree	int
+    marked	bool		// Lost a child since it became a child itself.
+    gone	bool		// Set once the key left the heap, so a stale handle is caught.
+
+    parent	*fibNode
+    child	*fibNode
+    left	*fibNode
+    right	*fibNode
+}
+
+
+func (fn *fibNode) Value() int {
+    return fn.key
+}
+
+
+/*
+The degree of a node of a Fibonacci heap of n nodes is at most log_phi(n) < 1.45 log2(n).
+ */
+const maxFibDegree = 96
+
+
+/*
+Create a new Fibonacci heap.
+ */
+func NewFibonacciHeap() *FibonacciHeap {
+    return &FibonacciHeap{}
+}
+
+
+func (fh *FibonacciHeap) Insert(value int) {
+    fh.Push(value)
+}
+
+
+func (fh *FibonacciHeap) Push(value int) Handle {
+    node := &fibNode{key: value}
+    node.left = node
+    node.right = node
+
+    fh.addRoot(node)
+    fh.size += 1
+    return node
+}
+
+
+func (fh *FibonacciHeap) Pop() int {
+    if fh.min == nil {
+        panic("FibonacciHeap: Pop() on an empty heap")
+    }
+
+    minnode := fh.min
+    fh.removeMin()
+    return minnode.key
+}
+
+
+func (fh *FibonacciHeap) Peek() int {
+    if fh.min == nil {
+        panic("FibonacciHeap: Peek() on an empty heap")
+    }
+    return fh.min.key
+}
+
+
+func (fh *FibonacciHeap) PeekHandle() Handle {
+    if fh.min == nil {
+        panic("FibonacciHeap: PeekHandle() on an empty heap")
+    }
+    return fh.min
+}
+
+
+func (fh *FibonacciHeap) Size() int {
+    return fh.size
+}
+
+
+/*
+"other" must be another *FibonacciHeap. Its nodes now belong to this heap, so it is left empty.
+ */
+func (fh *FibonacciHeap) Merge(pq PriorityQueue) {
+    other, ok := pq.(*FibonacciHeap)
+    if !ok {
+        panic("FibonacciHeap: can only merge with another *FibonacciHeap")
+    }
+    if other == fh {
+        panic("FibonacciHeap: can't merge a heap with itself")
+    }
+
+    if other.min != nil {
+        fh.addRoot(other.min)
+        fh.size += other.size
+    }
+    other.min = nil
+    other.size = 0
+}
+
+
+func (fh *FibonacciHeap) DecreaseKey(h Handle, value int) {
+    node := fh.handleNode(h)
+    if value > node.key {
+        panic("FibonacciHeap: DecreaseKey() to a larger key")
+    }
+
+    node.key = value
+    if node.parent != nil && node.key < node.parent.key {
+        fh.cascadingCut(node)
+    }
+    if node.key < fh.min.key {
+        fh.min = node
+    }
+}
+
+
+/*
+Cut the node to the root list as if its key went below every other key, then pop it. O(logn) amortized.
+ */
+func (fh *FibonacciHeap) Delete(h Handle) {
+    node := fh.handleNode(h)
+    if node.parent != nil {
+        fh.cascadingCut(node)
+    }
+    fh.min = node
+    fh.removeMin()
+}
+
+
+func (fh *FibonacciHeap) handleNode(h Handle) *fibNode {
+    node, ok := h.(*fibNode)
+    if !ok || node.gone {
+        panic("FibonacciHeap: handle does not belong to a key of a Fibonacci heap")
+    }
+    return node
+}
+
+
+/*
+Splice a circular list of roots into the root list.
+ */
+func (fh *FibonacciHeap) addRoot(list *fibNode) {
+    if fh.min == nil {
+        fh.min = list
+        return
+    }
+
+    splice(fh.min, list)
+    if list.key < fh.min.key {
+        fh.min = list
+    }
+}
+
+
+/*
+Splice the circular list "b" in after "a".
+ */
+func splice(a *fibNode, b *fibNode) {
+    aright := a.right
+    bleft := b.left
+
+    a.right = b
+    b.left = a
+    bleft.right = aright
+    aright.left = bleft
+}
+
+
+/*
+Unlink a node from its circular list, leaving it as a list of its own.
+ */
+func (fn *fibNode) unlink() {
+    fn.left.right = fn.right
+    fn.right.left = fn.left
+    fn.left = fn
+    fn.right = fn
+}
+
+
+/*
+Take "fh.min" out of the heap: its children become roots, then trees of the same degree are linked.
+ */
+func (fh *FibonacciHeap) removeMin() {
+    minnode := fh.min
+    fh.size -= 1
+
+    for child := minnode.child; child != nil; child = child.right {
+        child.parent = nil
+        child.marked = false
+        if child.right == minnode.child {
+            break
+        }
+    }
+    if minnode.child != nil {
+        splice(minnode, minnode.child)
+        minnode.child = nil
+    }
+
+    next := minnode.right
+    minnode.unlink()
+    minnode.gone = true
+    if next == minnode {
+        fh.min = nil
+        return
+    }
+
+    fh.consolidate(next)
+}
+
+
+/*
+Link the roots starting from "start" until every degree appears once, and find the new minimum.
+ */
+func (fh *FibonacciHeap) consolidate(start *fibNode) {
+    var slots [maxFibDegree]*fibNode
+
+    // Unlinking the roots as we go makes the walk simple: take the next root until none is left.
+    for root := start; root != nil; {
+        var next *fibNode
+        if root.right != root {
+            next = root.right
+        }
+        root.unlink()
+
+        for slots[root.degree] != nil {
+            other := slots[root.degree]
+            slots[root.degree] = nil
+            if other.key < root.key {
+                root, other = other, root
+            }
+            root.adopt(other)
+        }
+        slots[root.degree] = root
+        root = next
+    }
+
+    fh.min = nil
+    for _, root := range slots {
+        if root != nil {
+            fh.addRoot(root)
+        }
+    }
+}
+
+
+/*
+Make a root the last child of this node.
+ */
+func (fn *fibNode) adopt(other *fibNode) {
+    other.parent = fn
+    other.marked = false
+    if fn.child == nil {
+        fn.child = other
+    } else {
+        splice(fn.child.left, other)
+    }
+    fn.degree += 1
+}
+
+
+/*
+Move a node to the root list. Its parent is moved as well if it had lost a child already, and so on up the tree.
+ */
+func (fh *FibonacciHeap) cascadingCut(node *fibNode) {
+    for node.parent != nil {
+        parent := node.parent
+
+        if parent.child == node {
+            parent.child = node.right
+            if node.right == node {
+                parent.child = nil
+            }
+        }
+        node.unlink()
+        parent.degree -= 1
+        node.parent = nil
+        node.marked = false
+        fh.addRoot(node)
+
+        if !parent.marked {
+            if parent.parent != nil {
+                parent.marked = true
+            }
+            return
+        }
+        node = parent
+    }
+}
@@ -0,0 +1,58 @@
+package BrodalOkasakiHeap
+
+
+import "testing"
+
+
+func Test_fibonacci_stale_handle(t *testing.T) {
+    fh := NewFibonacciHeap()
+    handle := fh.Push(1)
+    fh.Push(2)
+    fh.Pop()
+
+    mustPanic(t, func() { fh.DecreaseKey(handle, 0) })
+    mustPanic(t, func() { fh.Delete(handle) })
+    if fh.Size() != 1 || fh.Peek() != 2 {t.Errorf("stale handle changed the heap")}
+}
+
+
+func Test_fibonacci_merge_self(t *testing.T) {
+    fh := NewFibonacciHeap()
+    insert_mult_pq(fh, interval(0, 10))
+
+    mustPanic(t, func() { fh.Merge(fh) })
+    if fh.Size() != 10 {t.Errorf("expected size 10, got %d", fh.Size())}
+    for i:=0; i<10; i++ {
+        if pval := fh.Pop(); pval != i {t.Fatalf("expected %d, got %d", i, pval)}
+    }
+}
+
+
+func Test_fibonacci_cascading_cut(t *testing.T) {
+    fh := NewFibonacciHeap()
+    handles := make([]Handle, 9)
+    for i := range handles {
+        handles[i] = fh.Push(i)
+    }
+
+    // Popping 0 links the other 8 keys into a single tree of degree 3.
+    fh.Pop()
+    if fh.min.right != fh.min || fh.min.degree != 3 {t.Fatalf("expected a single tree of degree 3")}
+
+    // 6 sits below a node that is not a root. Cutting 6 marks that node, cutting its other children cuts it too.
+    parent := handles[6].(*fibNode).parent
+    if parent == nil || parent.parent == nil {t.Fatalf("expected 6 two levels below the root")}
+    fh.DecreaseKey(handles[6], -6)
+    if !parent.marked {t.Errorf("parent %d not marked after losing a child", parent.key)}
+    for parent.child != nil {
+        fh.DecreaseKey(parent.child, parent.child.key - 100)
+    }
+    if parent.parent != nil || parent.marked {t.Errorf("marked parent %d not cut", parent.key)}
+
+    var last int
+    for i:=0; fh.Size() > 0; i++ {
+        pval := fh.Pop()
+        if i > 0 && pval < last {t.Fatalf("%d popped after %d", pval, last)}
+        last = pval
+    }
+}
@@ -0,0 +1,88 @@
+package BrodalOkasakiHeap
+
+import "fmt"
+
+
+/*
+Helpers for the heaps that keep a forest of trees instead of a single root, SkewBinomialHeap and BinomialHeap. The trees
+are the rank ordered children of a header node that holds no key itself.
+ */
+
+
+/*
+Take the children of "bon" into "forest". Every tree, ours and theirs, is carried into a table by rank, linking two
+trees of the same rank into one of the next rank until there is a free slot; then the table is read back in rank order.
+There are O(logn) trees and each link leaves one less of them, so this takes O(logn) time.
+
+The forest comes out with every rank at most once, which is a valid binomial and skew-binomial forest alike.
+ */
+func meldForest(forest *BONode, bon *BONode, order Order) {
+    var slots [maxRank]*BONode
+    carryChildren(&slots, forest, order)
+    carryChildren(&slots, bon, order)
+
+    var tail *BONode
+    for _, tree := range slots {
+        if tree == nil {
+            continue
+        }
+        tree.parent = forest
+        tree.leftsibling = tail
+        if tail == nil {
+            forest.children_head = tree
+        } else {
+            tail.rightsibling = tree
+        }
+        tail = tree
+    }
+}
+
+
+/*
+Unlink every child of "bon" and add it to "slots" with a binary carry, linking in the given order.
+ */
+func carryChildren(slots *[maxRank]*BONode, bon *BONode, order Order) {
+    for bon.children_head != nil {
+        tree := bon.children_head
+        tree.rogue()
+
+        for slots[tree.rank] != nil {
+            rank := tree.rank
+            tree = simpleLink(order, slots[rank], tree)
+            slots[rank] = nil
+        }
+        slots[tree.rank] = tree
+    }
+}
+
+
+/*
+Check the heap order within every tree, the links, the rank order of the forest and the size. Only a skew-binomial
+forest may have two trees of the same rank, and only as its two smallest.
+ */
+func validateForest(forest *BONode, size int, skew bool) error {
+    count := 0
+    var prev *BONode
+
+    for tree := forest.children_head; tree != nil; tree = tree.rightsibling {
+        if tree.parent != forest || tree.leftsibling != prev {
+            return fmt.Errorf("tree %d is not linked to the forest properly", tree.value)
+        }
+        if prev != nil && prev.rank > tree.rank {
+            return fmt.Errorf("trees are not rank ordered")
+        }
+        if prev != nil && prev.rank == tree.rank && (!skew || prev.leftsibling != nil) {
+            return fmt.Errorf("more than one tree of rank %d", tree.rank)
+        }
+
+        if err := tree.validate(MinFirst, &count, size); err != nil {
+            return err
+        }
+        prev = tree
+    }
+
+    if count != size {
+        return fmt.Errorf("heap holds %d nodes but has size %d", count, size)
+    }
+    return nil
+}
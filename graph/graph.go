@@ -0,0 +1,269 @@
+/*
+Package graph finds shortest paths and minimum spanning trees with the priority queues of BrodalOkasakiHeap.
+
+Vertices are numbered 0 to n-1, and edge weights are non-negative ints:
+
+    g := graph.AdjacencyList{
+        {{To: 1, Weight: 4}, {To: 2, Weight: 1}},
+        {},
+        {{To: 1, Weight: 2}},
+    }
+    paths := graph.Dijkstra(g, 0, nil)
+    paths.PathTo(1)  // [0 2 1]
+
+Any BrodalOkasakiHeap.PriorityQueue does the job. An AddressablePriorityQueue is used through its handles, so every
+vertex is in the queue at most once and an improved distance is a DecreaseKey(). Any other queue gets the improved
+distance as a new key, and the old one is skipped once it comes out.
+ */
+package graph
+
+import (
+    "github.com/cngkaygusuz/BrodalOkasakiHeap"
+)
+
+
+type Edge struct {
+    To		int
+    Weight	int
+}
+
+
+/*
+A directed graph. For an undirected one, list every edge in both directions.
+ */
+type Graph interface {
+    Vertices()		int			// The amount of vertices, numbered from 0.
+    Edges(v int)	[]Edge		// The edges going out of "v".
+}
+
+
+/*
+The simplest Graph: the edges going out of vertex "v" are at index "v".
+ */
+type AdjacencyList [][]Edge
+
+
+func (al AdjacencyList) Vertices() int			{ return len(al) }
+func (al AdjacencyList) Edges(v int) []Edge		{ return al[v] }
+
+
+/*
+Shortest paths from a source vertex to every vertex, as found by Dijkstra().
+ */
+type ShortestPaths struct {
+    source	int
+    dist	[]int
+    prev	[]int		// The vertex before every vertex on its shortest path, -1 for the source and the unreached.
+    reached	[]bool
+}
+
+
+/*
+The length of the shortest path to "v", and whether there is a path at all.
+ */
+func (sp *ShortestPaths) Dist(v int) (int, bool) {
+    return sp.dist[v], sp.reached[v]
+}
+
+
+/*
+The vertices of a shortest path to "v", the source first and "v" last. nil if there is no path.
+ */
+func (sp *ShortestPaths) PathTo(v int) []int {
+    if !sp.reached[v] {
+        return nil
+    }
+    return walkBack(sp.prev, v)
+}
+
+
+func walkBack(prev []int, v int) []int {
+    var path []int
+    for ; v != -1; v = prev[v] {
+        path = append(path, v)
+    }
+    for i, j := 0, len(path)-1; i < j; i, j = i+1, j-1 {
+        path[i], path[j] = path[j], path[i]
+    }
+    return path
+}
+
+
+/*
+Find the shortest paths from "source" to every vertex. "pq" must be empty, nil uses a new BOHeap. Panics on a negative
+edge weight.
+
+O((n+m)logn) time with a queue whose Pop() is O(logn), O(m + nlogn) with an addressable queue whose DecreaseKey() is
+O(1), amortized or not.
+ */
+func Dijkstra(g Graph, source int, pq BrodalOkasakiHeap.PriorityQueue) *ShortestPaths {
+    s := newSearch(g, pq, nil)
+    s.relax(source, -1, 0)
+
+    for {
+        v, ok := s.next()
+        if !ok {
+            break
+        }
+        s.expand(v)
+    }
+
+    return &ShortestPaths{source: source, dist: s.dist, prev: s.prev, reached: s.reached}
+}
+
+
+/*
+Find a shortest path from "source" to "target", guided by "heuristic": an estimate of the distance from a vertex to
+the target. The heuristic must be consistent, i.e. never drop by more than the weight of an edge along that edge, and
+must be 0 at the target, or the path found may not be the shortest. Manhattan distance on a grid of unit weights is one.
+
+Returns the vertices of the path, the source first, and its length. ok is false when the target can't be reached.
+ */
+func AStar(g Graph, source int, target int, heuristic func(v int) int, pq BrodalOkasakiHeap.PriorityQueue) (path []int, dist int, ok bool) {
+    s := newSearch(g, pq, heuristic)
+    s.relax(source, -1, 0)
+
+    for {
+        v, ok := s.next()
+        if !ok {
+            return nil, 0, false
+        }
+        if v == target {
+            return walkBack(s.prev, v), s.dist[v], true
+        }
+        s.expand(v)
+    }
+}
+
+
+// ====== Search ======
+
+
+/*
+The state shared by Dijkstra(), AStar() and Prim(): tentative distances, and the queue of vertices keyed by distance,
+plus the heuristic for A*.
+
+An addressable queue tells which vertex is on top through PeekHandle(). Any other queue holds the keys of a
+BrodalOkasakiHeap.KeyedQueue, where an improved distance removes the entry of the old one.
+ */
+type search struct {
+    g			Graph
+    heuristic	func(v int) int
+
+    // Either "apq" with the handles of the vertices, or "queue" with their entries.
+    apq			BrodalOkasakiHeap.AddressablePriorityQueue
+    handles		[]BrodalOkasakiHeap.Handle
+    vertices	map[BrodalOkasakiHeap.Handle]int
+    queue		*BrodalOkasakiHeap.KeyedQueue[int]
+    entries		[]*BrodalOkasakiHeap.KeyedEntry[int]
+
+    dist		[]int
+    prev		[]int
+    reached		[]bool
+    settled		[]bool
+}
+
+
+func newSearch(g Graph, pq BrodalOkasakiHeap.PriorityQueue, heuristic func(v int) int) *search {
+    if pq == nil {
+        pq = BrodalOkasakiHeap.NewBOHeap()
+    }
+    if pq.Size() != 0 {
+        panic("graph: the queue must be empty")
+    }
+
+    n := g.Vertices()
+    s := &search {
+        g: g,
+        heuristic: heuristic,
+        dist: make([]int, n),
+        prev: make([]int, n),
+        reached: make([]bool, n),
+        settled: make([]bool, n),
+    }
+    if apq, ok := pq.(BrodalOkasakiHeap.AddressablePriorityQueue); ok {
+        s.apq = apq
+        s.handles = make([]BrodalOkasakiHeap.Handle, n)
+        s.vertices = make(map[BrodalOkasakiHeap.Handle]int)
+    } else {
+        s.queue = BrodalOkasakiHeap.NewKeyedQueue[int](pq)
+        s.entries = make([]*BrodalOkasakiHeap.KeyedEntry[int], n)
+    }
+    for i := range s.prev {
+        s.prev[i] = -1
+    }
+    return s
+}
+
+
+func (s *search) key(v int) int {
+    if s.heuristic == nil {
+        return s.dist[v]
+    }
+    return s.dist[v] + s.heuristic(v)
+}
+
+
+/*
+Reach "v" from "from" with a path of length "dist", if that is shorter than what we have.
+ */
+func (s *search) relax(v int, from int, dist int) {
+    if s.settled[v] || (s.reached[v] && dist >= s.dist[v]) {
+        return
+    }
+
+    improved := s.reached[v]
+    s.dist[v] = dist
+    s.prev[v] = from
+    s.reached[v] = true
+
+    key := s.key(v)
+    switch {
+    case s.apq == nil:
+        if improved {
+            s.queue.Remove(s.entries[v])
+        }
+        s.entries[v] = s.queue.Push(key, v)
+    case improved:
+        s.apq.DecreaseKey(s.handles[v], key)
+    default:
+        s.handles[v] = s.apq.Push(key)
+        s.vertices[s.handles[v]] = v
+    }
+}
+
+
+/*
+Settle the closest vertex that is not settled yet. ok is false when there is none.
+ */
+func (s *search) next() (v int, ok bool) {
+    if s.apq != nil {
+        if s.apq.Size() == 0 {
+            return 0, false
+        }
+        handle := s.apq.PeekHandle()
+        v = s.vertices[handle]
+        delete(s.vertices, handle)
+        s.apq.Pop()
+
+        s.settled[v] = true
+        return v, true
+    }
+
+    if s.queue.Size() == 0 {
+        return 0, false
+    }
+    v = s.queue.Pop().Value()
+    s.settled[v] = true
+    return v, true
+}
+
+
+func (s *search) expand(v int) {
+    for _, edge := range s.g.Edges(v) {
+        if edge.Weight < 0 {
+            panic("graph: negative edge weight")
+        }
+        s.relax(edge.To, v, s.dist[v] + edge.Weight)
+    }
+}
@@ -0,0 +1,211 @@
+package graph
+
+
+import (
+    "math/rand"
+    "testing"
+
+    "github.com/cngkaygusuz/BrodalOkasakiHeap"
+)
+
+
+var queues = []struct {
+    name		string
+    newQueue	func() BrodalOkasakiHeap.PriorityQueue
+}{
+    {"BOHeap", func() BrodalOkasakiHeap.PriorityQueue { return BrodalOkasakiHeap.NewBOHeap() }},
+    {"PairingHeap", func() BrodalOkasakiHeap.PriorityQueue { return BrodalOkasakiHeap.NewPairingHeap() }},
+    {"FibonacciHeap", func() BrodalOkasakiHeap.PriorityQueue { return BrodalOkasakiHeap.NewFibonacciHeap() }},
+    {"StrictFibonacciHeap", func() BrodalOkasakiHeap.PriorityQueue { return BrodalOkasakiHeap.NewStrictFibonacciHeap() }},
+}
+
+
+/*
+A w x h grid, every cell connected to its 4 neighbours in both directions with a random weight in [1, maxweight].
+ */
+func grid(rng *rand.Rand, w int, h int, maxweight int) AdjacencyList {
+    g := make(AdjacencyList, w*h)
+    connect := func(a int, b int) {
+        weight := 1 + rng.Intn(maxweight)
+        g[a] = append(g[a], Edge{b, weight})
+        g[b] = append(g[b], Edge{a, weight})
+    }
+
+    for y:=0; y<h; y++ {
+        for x:=0; x<w; x++ {
+            if x+1 < w {
+                connect(y*w + x, y*w + x+1)
+            }
+            if y+1 < h {
+                connect(y*w + x, (y+1)*w + x)
+            }
+        }
+    }
+    return g
+}
+
+
+/*
+n vertices and m random directed edges with weights in [0, maxweight], so some vertices may be unreachable.
+ */
+func randomGraph(rng *rand.Rand, n int, m int, maxweight int) AdjacencyList {
+    g := make(AdjacencyList, n)
+    for i:=0; i<m; i++ {
+        from := rng.Intn(n)
+        g[from] = append(g[from], Edge{rng.Intn(n), rng.Intn(maxweight + 1)})
+    }
+    return g
+}
+
+
+/*
+Bellman-Ford: relax every edge until nothing changes. Slow, but too simple to get wrong.
+ */
+func naiveDistances(g Graph, source int) ([]int, []bool) {
+    n := g.Vertices()
+    dist := make([]int, n)
+    reached := make([]bool, n)
+    reached[source] = true
+
+    for changed := true; changed; {
+        changed = false
+        for v:=0; v<n; v++ {
+            if !reached[v] {
+                continue
+            }
+            for _, edge := range g.Edges(v) {
+                if !reached[edge.To] || dist[v] + edge.Weight < dist[edge.To] {
+                    dist[edge.To] = dist[v] + edge.Weight
+                    reached[edge.To] = true
+                    changed = true
+                }
+            }
+        }
+    }
+    return dist, reached
+}
+
+
+/*
+Check that "path" goes from "source" to "target" along edges of the graph, with a total weight of "dist".
+ */
+func checkPath(t *testing.T, g Graph, path []int, source int, target int, dist int) {
+    t.Helper()
+
+    if len(path) == 0 || path[0] != source || path[len(path)-1] != target {
+        t.Fatalf("path %v does not go from %d to %d", path, source, target)
+    }
+    total := 0
+    for i:=1; i<len(path); i++ {
+        best := -1
+        for _, edge := range g.Edges(path[i-1]) {
+            if edge.To == path[i] && (best == -1 || edge.Weight < best) {
+                best = edge.Weight
+            }
+        }
+        if best == -1 {
+            t.Fatalf("path %v uses a missing edge %d -> %d", path, path[i-1], path[i])
+        }
+        total += best
+    }
+    if total != dist {
+        t.Fatalf("path %v has length %d, expected %d", path, total, dist)
+    }
+}
+
+
+func checkDijkstra(t *testing.T, g Graph, source int) {
+    t.Helper()
+    expected, reachable := naiveDistances(g, source)
+
+    for _, q := range queues {
+        paths := Dijkstra(g, source, q.newQueue())
+        for v := range expected {
+            dist, ok := paths.Dist(v)
+            if ok != reachable[v] || (ok && dist != expected[v]) {
+                t.Fatalf("%s: vertex %d: expected %d (%t), got %d (%t)", q.name, v, expected[v], reachable[v], dist, ok)
+            }
+            if ok {
+                checkPath(t, g, paths.PathTo(v), source, v, dist)
+            } else if paths.PathTo(v) != nil {
+                t.Fatalf("%s: path to unreachable vertex %d", q.name, v)
+            }
+        }
+    }
+}
+
+
+func Test_dijkstra_example(t *testing.T) {
+    g := AdjacencyList {
+        {{1, 4}, {2, 1}},
+        {},
+        {{1, 2}},
+    }
+    paths := Dijkstra(g, 0, nil)
+
+    if dist, _ := paths.Dist(1); dist != 3 {t.Errorf("expected distance 3, got %d", dist)}
+    if path := paths.PathTo(1); len(path) != 3 || path[1] != 2 {t.Errorf("expected path [0 2 1], got %v", path)}
+}
+
+
+func Test_dijkstra_grid(t *testing.T) {
+    rng := rand.New(rand.NewSource(1))
+    for i:=0; i<5; i++ {
+        g := grid(rng, 15, 10, 9)
+        checkDijkstra(t, g, rng.Intn(len(g)))
+    }
+}
+
+
+func Test_dijkstra_random(t *testing.T) {
+    rng := rand.New(rand.NewSource(2))
+    for i:=0; i<20; i++ {
+        // Small weights, zero included, make for plenty of ties.
+        g := randomGraph(rng, 100, 300 + rng.Intn(300), 5)
+        checkDijkstra(t, g, rng.Intn(len(g)))
+    }
+}
+
+
+func Test_dijkstra_negative_weight(t *testing.T) {
+    g := AdjacencyList{{{1, -1}}, {}}
+    defer func() {
+        if recover() == nil {t.Errorf("negative weight did not panic")}
+    }()
+    Dijkstra(g, 0, nil)
+}
+
+
+func Test_astar_grid(t *testing.T) {
+    const (
+        W = 30
+        H = 20
+    )
+    rng := rand.New(rand.NewSource(3))
+
+    for i:=0; i<10; i++ {
+        g := grid(rng, W, H, 9)
+        source, target := rng.Intn(W*H), rng.Intn(W*H)
+        expected, _ := naiveDistances(g, source)
+
+        // Every weight is at least 1, so Manhattan distance is consistent.
+        manhattan := func(v int) int {
+            dx, dy := v%W - target%W, v/W - target/W
+            if dx < 0 { dx = -dx }
+            if dy < 0 { dy = -dy }
+            return dx + dy
+        }
+
+        for _, q := range queues {
+            path, dist, ok := AStar(g, source, target, manhattan, q.newQueue())
+            if !ok || dist != expected[target] {t.Fatalf("%s: expected %d, got %d (%t)", q.name, expected[target], dist, ok)}
+            checkPath(t, g, path, source, target, dist)
+        }
+    }
+}
+
+
+func Test_astar_unreachable(t *testing.T) {
+    g := AdjacencyList{{{1, 1}}, {}, {{0, 1}}}
+    if _, _, ok := AStar(g, 0, 2, func(int) int { return 0 }, nil); ok {t.Errorf("found a path to an unreachable vertex")}
+}
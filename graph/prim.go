@@ -0,0 +1,47 @@
+package graph
+
+import (
+    "github.com/cngkaygusuz/BrodalOkasakiHeap"
+)
+
+
+type TreeEdge struct {
+    From	int
+    To		int
+    Weight	int
+}
+
+
+/*
+Find a minimum spanning tree of an undirected graph, one that lists every edge in both directions. For a graph that
+is not connected, it is a minimum spanning forest: a tree for every connected component. Negative weights are fine.
+
+Returns the edges of the tree, in the order they were added, and their total weight. "pq" is used the same way as by
+Dijkstra(), the key of a vertex being the weight of the lightest edge from the tree to it.
+ */
+func Prim(g Graph, pq BrodalOkasakiHeap.PriorityQueue) (edges []TreeEdge, weight int) {
+    s := newSearch(g, pq, nil)
+
+    for root:=0; root<g.Vertices(); root++ {
+        if s.settled[root] {
+            continue
+        }
+        s.relax(root, -1, 0)
+
+        for {
+            v, ok := s.next()
+            if !ok {
+                break
+            }
+            if v != root {
+                edges = append(edges, TreeEdge{s.prev[v], v, s.dist[v]})
+                weight += s.dist[v]
+            }
+
+            for _, edge := range g.Edges(v) {
+                s.relax(edge.To, v, edge.Weight)
+            }
+        }
+    }
+    return edges, weight
+}
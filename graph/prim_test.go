@@ -0,0 +1,114 @@
+package graph
+
+
+import (
+    "math/rand"
+    "sort"
+    "testing"
+)
+
+
+/*
+Kruskal with a plain union-find, returning the total weight and the amount of edges of a minimum spanning forest.
+ */
+func naiveMST(g Graph) (int, int) {
+    var edges []TreeEdge
+    for v:=0; v<g.Vertices(); v++ {
+        for _, edge := range g.Edges(v) {
+            edges = append(edges, TreeEdge{v, edge.To, edge.Weight})
+        }
+    }
+    sort.Slice(edges, func(i int, j int) bool { return edges[i].Weight < edges[j].Weight })
+
+    parent := make([]int, g.Vertices())
+    for i := range parent {
+        parent[i] = i
+    }
+    var find func(v int) int
+    find = func(v int) int {
+        if parent[v] != v {
+            parent[v] = find(parent[v])
+        }
+        return parent[v]
+    }
+
+    weight, count := 0, 0
+    for _, edge := range edges {
+        if a, b := find(edge.From), find(edge.To); a != b {
+            parent[a] = b
+            weight += edge.Weight
+            count += 1
+        }
+    }
+    return weight, count
+}
+
+
+/*
+An undirected random graph: every edge is listed in both directions. Weights may be negative.
+ */
+func randomUndirected(rng *rand.Rand, n int, m int) AdjacencyList {
+    g := make(AdjacencyList, n)
+    for i:=0; i<m; i++ {
+        a, b := rng.Intn(n), rng.Intn(n)
+        weight := rng.Intn(20) - 5
+        g[a] = append(g[a], Edge{b, weight})
+        g[b] = append(g[b], Edge{a, weight})
+    }
+    return g
+}
+
+
+func hasEdge(g Graph, edge TreeEdge) bool {
+    for _, e := range g.Edges(edge.From) {
+        if e.To == edge.To && e.Weight == edge.Weight {
+            return true
+        }
+    }
+    return false
+}
+
+
+func checkPrim(t *testing.T, g Graph) {
+    t.Helper()
+    expected, count := naiveMST(g)
+
+    for _, q := range queues {
+        edges, weight := Prim(g, q.newQueue())
+        if weight != expected || len(edges) != count {
+            t.Fatalf("%s: expected weight %d with %d edges, got %d with %d", q.name, expected, count, weight, len(edges))
+        }
+
+        // The edges must exist, and span a forest: every vertex is reached at most once.
+        total := 0
+        seen := make(map[int]bool)
+        for _, edge := range edges {
+            if !hasEdge(g, edge) {
+                t.Fatalf("%s: edge %v is not in the graph", q.name, edge)
+            }
+            if seen[edge.To] {
+                t.Fatalf("%s: vertex %d reached twice", q.name, edge.To)
+            }
+            seen[edge.To] = true
+            total += edge.Weight
+        }
+        if total != weight {t.Fatalf("%s: edges weigh %d, reported %d", q.name, total, weight)}
+    }
+}
+
+
+func Test_prim_grid(t *testing.T) {
+    rng := rand.New(rand.NewSource(4))
+    for i:=0; i<5; i++ {
+        checkPrim(t, grid(rng, 15, 10, 20))
+    }
+}
+
+
+func Test_prim_random(t *testing.T) {
+    rng := rand.New(rand.NewSource(5))
+    for i:=0; i<20; i++ {
+        // Sparse enough to leave a few components.
+        checkPrim(t, randomUndirected(rng, 100, 80 + rng.Intn(200)))
+    }
+}
@@ -0,0 +1,120 @@
+package BrodalOkasakiHeap
+
+import "reflect"
+
+
+/*
+Lets a PriorityQueue stand in for a "container/heap.Interface", for code written against that package:
+
+    h := NewHeapAdapter(NewBOHeap())
+    heap.Push(h, 3)
+    heap.Push(h, 1)
+    heap.Pop(h)  // 1
+
+The queue keeps its own order, so Less() and Swap() are there only to satisfy the interface: Less() reports false and
+Swap() does nothing, which makes the sifting done by heap.Init(), heap.Push() and heap.Pop() a no-op. heap.Fix() and
+heap.Remove() address elements by index, which a PriorityQueue has no notion of, so they must not be used. Pushed
+values must be ints.
+ */
+type HeapAdapter struct {
+    pq	PriorityQueue
+}
+
+
+func NewHeapAdapter(pq PriorityQueue) *HeapAdapter {
+    return &HeapAdapter{pq: pq}
+}
+
+
+func (ha *HeapAdapter) Len() int				{ return ha.pq.Size() }
+func (ha *HeapAdapter) Less(i, j int) bool		{ return false }
+func (ha *HeapAdapter) Swap(i, j int)			{}
+func (ha *HeapAdapter) Push(x interface{})		{ ha.pq.Insert(x.(int)) }
+func (ha *HeapAdapter) Pop() interface{}		{ return ha.pq.Pop() }
+
+
+/*
+The queue behind the adapter.
+ */
+func (ha *HeapAdapter) Queue() PriorityQueue {
+    return ha.pq
+}
+
+
+/*
+Sort the ints in increasing order, by inserting them all into a BOHeap and popping them back. O(nlogn) time.
+ */
+func HeapSort(values []int) {
+    if len(values) < 2 {
+        return
+    }
+
+    // One slab for all the nodes.
+    bq := NewPooledBOHeap(len(values), 0)
+    for _, value := range values {
+        bq.Insert(value)
+    }
+    for i := range values {
+        values[i] = bq.Pop()
+    }
+}
+
+
+/*
+Sort "n" elements by an int key, the smallest key first, using a BOHeap. "key(i)" is the key of the element at "i",
+called once for every index before anything moves, and "swap" moves the elements. The heap only holds ints, so the
+elements are never compared with each other, only their keys are. Elements of equal key keep their order, so the sort
+is stable.
+
+The heap only holds the keys, so the indices of the elements wait in a bucket per key. Popping the keys in order, and
+taking the indices out of their buckets first in first out, gives the sorted order of the indices, which is then
+carried out with at most n swaps.
+ */
+func HeapSortKeyed(n int, key func(i int) int, swap func(i int, j int)) {
+    if n < 2 {
+        return
+    }
+
+    bq := NewPooledBOHeap(n, 0)
+    buckets := make(map[int][]int)
+    for i:=0; i<n; i++ {
+        k := key(i)
+        bq.Insert(k)
+        buckets[k] = append(buckets[k], i)
+    }
+
+    // order[i] is the index, before sorting, of the element that belongs at "i".
+    order := make([]int, n)
+    for i := range order {
+        k := bq.Pop()
+        order[i] = buckets[k][0]
+        buckets[k] = buckets[k][1:]
+    }
+
+    // where[e] is the current position of element "e", at[p] is the element currently at position "p".
+    where := make([]int, n)
+    at := make([]int, n)
+    for i := range where {
+        where[i] = i
+        at[i] = i
+    }
+    for i, elem := range order {
+        pos := where[elem]
+        if pos == i {
+            continue
+        }
+        swap(i, pos)
+
+        displaced := at[i]
+        at[i], at[pos] = elem, displaced
+        where[elem], where[displaced] = i, pos
+    }
+}
+
+
+/*
+Same as HeapSortKeyed() for any slice, in the way of sort.Slice(). Panics if "slice" is not a slice.
+ */
+func HeapSortSlice(slice interface{}, key func(i int) int) {
+    HeapSortKeyed(reflect.ValueOf(slice).Len(), key, reflect.Swapper(slice))
+}
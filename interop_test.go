@@ -0,0 +1,73 @@
+package BrodalOkasakiHeap
+
+
+import (
+    "container/heap"
+    "math/rand"
+    "sort"
+    "testing"
+)
+
+
+func Test_heap_adapter(t *testing.T) {
+    const SIZE = 100
+    rand.Seed(1)
+
+    h := NewHeapAdapter(NewBOHeap())
+    heap.Init(h)
+    for _, elem := range shuffle(interval(0, SIZE)) {
+        heap.Push(h, elem)
+    }
+    if h.Len() != SIZE {t.Errorf("expected length %d, got %d", SIZE, h.Len())}
+
+    for i:=0; i<SIZE; i++ {
+        if pval := heap.Pop(h).(int); pval != i {t.Errorf("expected %d, got %d", i, pval)}
+    }
+}
+
+
+func Test_heapsort_ints(t *testing.T) {
+    const SIZE = 1000
+    rand.Seed(1)
+
+    nums := shuffle(interval(0, SIZE))
+    nums = append(nums, nums[:100]...)
+    HeapSort(nums)
+    if !sort.IntsAreSorted(nums) {t.Errorf("not sorted: %v", nums)}
+}
+
+
+type person struct {
+    name	string
+    age		int
+}
+
+
+func Test_heapsort_slice_stable(t *testing.T) {
+    people := []person{{"ada", 36}, {"bob", 20}, {"cem", 36}, {"dan", 5}, {"eve", 20}, {"fay", 36}}
+    expected := []person{{"dan", 5}, {"bob", 20}, {"eve", 20}, {"ada", 36}, {"cem", 36}, {"fay", 36}}
+
+    HeapSortSlice(people, func(i int) int { return people[i].age })
+    for i := range expected {
+        if people[i] != expected[i] {t.Fatalf("expected %v, got %v", expected, people)}
+    }
+}
+
+
+func Test_heapsort_keyed(t *testing.T) {
+    rng := rand.New(rand.NewSource(1))
+    words := make([]string, 500)
+    for i := range words {
+        words[i] = string(rune('a' + rng.Intn(26))) + string(rune('a' + rng.Intn(26)))
+    }
+
+    // Sort by the first letter only, which must leave the words of the same first letter in their original order.
+    expected := make([]string, len(words))
+    copy(expected, words)
+    sort.SliceStable(expected, func(i int, j int) bool { return expected[i][0] < expected[j][0] })
+
+    HeapSortKeyed(len(words), func(i int) int { return int(words[i][0]) }, sort.StringSlice(words).Swap)
+    for i := range expected {
+        if words[i] != expected[i] {t.Fatalf("at %d: expected %s, got %s", i, expected[i], words[i])}
+    }
+}
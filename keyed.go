@@ -0,0 +1,195 @@
+package BrodalOkasakiHeap
+
+
+/*
+A value waiting in a KeyedQueue under an int key, the handle to remove it with.
+ */
+type KeyedEntry[T any] struct {
+    key		int
+    value	T
+    queued	bool
+}
+
+
+func (e *KeyedEntry[T]) Key() int {
+    return e.key
+}
+
+
+func (e *KeyedEntry[T]) Value() T {
+    return e.value
+}
+
+
+/*
+Report whether the entry is still in its queue, not popped nor removed.
+ */
+func (e *KeyedEntry[T]) Queued() bool {
+    return e.queued
+}
+
+
+/*
+A priority queue of values of any type, each under an int key. Values of equal key come out first come first served,
+so a queue filled the same way always empties the same way.
+
+The keys live in a PriorityQueue, which only holds ints, so the values wait in a bucket per key. Whenever a key comes
+out of the queue, the value that has waited the longest under it is the one.
+
+A PriorityQueue can't give up a key from the middle, so a removed value stays in its bucket, with its key in the queue,
+and both are thrown away once they reach the front. A value removed long before its key comes up, like a timeout that
+is cancelled almost every time, would linger for that long, so once the removed values outnumber the queued ones, the
+queue is rebuilt without them, read compact().
+ */
+type KeyedQueue[T any] struct {
+    keys		PriorityQueue
+    buckets		map[int][]*KeyedEntry[T]
+    size		int
+    removed		int		// Removed entries still in their buckets.
+}
+
+
+/*
+Create a new keyed queue on top of "keys", which must be empty, and decides the order the keys come out in. A nil
+"keys" means a new BOHeap.
+ */
+func NewKeyedQueue[T any](keys PriorityQueue) *KeyedQueue[T] {
+    if keys == nil {
+        keys = NewBOHeap()
+    }
+    if keys.Size() != 0 {
+        panic("KeyedQueue: the queue of keys must be empty")
+    }
+
+    return &KeyedQueue[T] {
+        keys: keys,
+        buckets: make(map[int][]*KeyedEntry[T]),
+    }
+}
+
+
+/*
+The amount of values queued, not counting the removed ones.
+ */
+func (kq *KeyedQueue[T]) Size() int {
+    return kq.size
+}
+
+
+/*
+Queue "value" under "key". Takes the time of an Insert() into the queue of keys.
+ */
+func (kq *KeyedQueue[T]) Push(key int, value T) *KeyedEntry[T] {
+    e := &KeyedEntry[T]{key: key, value: value, queued: true}
+    kq.keys.Insert(key)
+    kq.buckets[key] = append(kq.buckets[key], e)
+    kq.size += 1
+    return e
+}
+
+
+/*
+Return the entry that comes out next, without removing it. Panics if the queue is empty.
+ */
+func (kq *KeyedQueue[T]) Peek() *KeyedEntry[T] {
+    if kq.size == 0 {
+        panic("KeyedQueue: Peek() on an empty queue")
+    }
+    kq.discardRemoved()
+    return kq.buckets[kq.keys.Peek()][0]
+}
+
+
+/*
+Remove the entry that comes out next and return it. Panics if the queue is empty.
+ */
+func (kq *KeyedQueue[T]) Pop() *KeyedEntry[T] {
+    if kq.size == 0 {
+        panic("KeyedQueue: Pop() on an empty queue")
+    }
+    kq.discardRemoved()
+
+    e := kq.popFront()
+    e.queued = false
+    kq.size -= 1
+    return e
+}
+
+
+/*
+Take an entry out of the queue. Returns false if it was popped or removed already.
+ */
+func (kq *KeyedQueue[T]) Remove(e *KeyedEntry[T]) bool {
+    if !e.queued {
+        return false
+    }
+    e.queued = false
+    kq.size -= 1
+    kq.removed += 1
+    if kq.removed > kq.size {
+        kq.compact()
+    }
+    return true
+}
+
+
+/*
+Throw away the removed entries at the front.
+ */
+func (kq *KeyedQueue[T]) discardRemoved() {
+    for kq.keys.Size() > 0 && !kq.buckets[kq.keys.Peek()][0].queued {
+        kq.popFront()
+        kq.removed -= 1
+    }
+}
+
+
+/*
+Empty the queue of keys, and put back the keys of the entries that are still queued, dropping the removed ones from
+their buckets. Takes O(nlogn) time for the n entries in the buckets, but happens only after more than n/2 removals
+since the last time, so Remove() stays amortized O(logn).
+ */
+func (kq *KeyedQueue[T]) compact() {
+    for kq.keys.Size() > 0 {
+        kq.keys.Pop()
+    }
+
+    for key, bucket := range kq.buckets {
+        queued := bucket[:0]
+        for _, e := range bucket {
+            if e.queued {
+                queued = append(queued, e)
+            }
+        }
+        for i := len(queued); i < len(bucket); i++ {
+            bucket[i] = nil
+        }
+
+        if len(queued) == 0 {
+            delete(kq.buckets, key)
+            continue
+        }
+        kq.buckets[key] = queued
+        for range queued {
+            kq.keys.Insert(key)
+        }
+    }
+    kq.removed = 0
+}
+
+
+/*
+Pop the first key of the queue, along with the first entry of its bucket.
+ */
+func (kq *KeyedQueue[T]) popFront() *KeyedEntry[T] {
+    key := kq.keys.Pop()
+    bucket := kq.buckets[key]
+    e := bucket[0]
+    if len(bucket) == 1 {
+        delete(kq.buckets, key)
+    } else {
+        bucket[0] = nil
+        kq.buckets[key] = bucket[1:]
+    }
+    return e
+}
@@ -0,0 +1,101 @@
+package BrodalOkasakiHeap
+
+
+import (
+    "math/rand"
+    "sort"
+    "testing"
+)
+
+
+func Test_keyed_fifo(t *testing.T) {
+    rng := rand.New(rand.NewSource(1))
+    kq := NewKeyedQueue[int](nil)
+
+    // Values are their insertion index, so equal keys must come out with increasing values.
+    type pair struct{ key, value int }
+    var expected []pair
+    for i:=0; i<1000; i++ {
+        key := rng.Intn(20)
+        kq.Push(key, i)
+        expected = append(expected, pair{key, i})
+    }
+    sort.SliceStable(expected, func(i int, j int) bool { return expected[i].key < expected[j].key })
+
+    for _, p := range expected {
+        if e := kq.Peek(); e.Key() != p.key || e.Value() != p.value {t.Fatalf("expected %v on top, got %d %d", p, e.Key(), e.Value())}
+        if e := kq.Pop(); e.Key() != p.key || e.Value() != p.value || e.Queued() {t.Fatalf("expected %v, got %d %d", p, e.Key(), e.Value())}
+    }
+    if kq.Size() != 0 {t.Errorf("expected an empty queue, got %d", kq.Size())}
+    mustPanic(t, func() { kq.Pop() })
+    mustPanic(t, func() { kq.Peek() })
+}
+
+
+func Test_keyed_order(t *testing.T) {
+    kq := NewKeyedQueue[string](NewMaxBOHeap())
+    kq.Push(1, "a")
+    kq.Push(3, "b")
+    kq.Push(3, "c")
+    kq.Push(2, "d")
+
+    for _, expected := range []string{"b", "c", "d", "a"} {
+        if value := kq.Pop().Value(); value != expected {t.Errorf("expected %s, got %s", expected, value)}
+    }
+}
+
+
+func Test_keyed_remove(t *testing.T) {
+    kq := NewKeyedQueue[string](nil)
+    a := kq.Push(1, "a")
+    b := kq.Push(1, "b")
+    c := kq.Push(2, "c")
+
+    if !kq.Remove(a) || kq.Remove(a) {t.Errorf("expected a to be removed exactly once")}
+    if a.Queued() || kq.Size() != 2 {t.Errorf("removed entry still counted")}
+    if e := kq.Peek(); e != b {t.Errorf("expected b on top, got %s", e.Value())}
+
+    kq.Pop()
+    if kq.Remove(b) {t.Errorf("removed a popped entry")}
+    kq.Remove(c)
+    if kq.Size() != 0 {t.Errorf("expected an empty queue, got %d", kq.Size())}
+    mustPanic(t, func() { kq.Pop() })
+}
+
+
+func Test_keyed_remove_bounded(t *testing.T) {
+    rng := rand.New(rand.NewSource(1))
+    kq := NewKeyedQueue[int](nil)
+    var live []*KeyedEntry[int]
+    for i:=0; i<10; i++ {
+        live = append(live, kq.Push(rng.Intn(1000), i))
+    }
+
+    // Keep replacing entries far from the front, which never get popped.
+    for i:=0; i<100000; i++ {
+        j := rng.Intn(len(live))
+        kq.Remove(live[j])
+        live[j] = kq.Push(1000 + rng.Intn(1000), i)
+
+        entries := 0
+        for _, bucket := range kq.buckets {
+            entries += len(bucket)
+        }
+        if kq.keys.Size() > 2*kq.Size()+1 || entries != kq.keys.Size() {t.Fatalf("op %d: %d keys and %d entries for %d values", i, kq.keys.Size(), entries, kq.Size())}
+    }
+
+    // Compacting kept the order and the ties.
+    var last *KeyedEntry[int]
+    for kq.Size() > 0 {
+        e := kq.Pop()
+        if last != nil && (e.Key() < last.Key() || e.Key() == last.Key() && e.Value() < last.Value()) {t.Fatalf("%d %d popped after %d %d", e.Key(), e.Value(), last.Key(), last.Value())}
+        last = e
+    }
+}
+
+
+func Test_keyed_nonempty_keys(t *testing.T) {
+    bq := NewBOHeap()
+    bq.Insert(1)
+    mustPanic(t, func() { NewKeyedQueue[int](bq) })
+}
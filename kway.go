@@ -0,0 +1,105 @@
+package BrodalOkasakiHeap
+
+import (
+    "context"
+    "errors"
+    "fmt"
+)
+
+
+/*
+A source of ints for MergeSorted(). Next() returns the next value, ok being false once the source is exhausted.
+ */
+type Iterator interface {
+    Next() (value int, ok bool)
+}
+
+
+/*
+Iterates over a slice.
+ */
+type SliceIterator struct {
+    values	[]int
+}
+
+
+func NewSliceIterator(values []int) *SliceIterator {
+    return &SliceIterator{values: values}
+}
+
+
+func (si *SliceIterator) Next() (int, bool) {
+    if len(si.values) == 0 {
+        return 0, false
+    }
+    value := si.values[0]
+    si.values = si.values[1:]
+    return value, true
+}
+
+
+var ErrUnsorted = errors.New("BrodalOkasakiHeap: source is not sorted")
+
+
+/*
+Merge sorted sources into one sorted stream, calling "emit" with every value in increasing order. Every source must be
+in increasing order itself. The heap holds one value per source, so merging n values from k sources takes O(nlogk)
+time and O(k) space.
+
+Equal values from different sources come out in the order they were read.
+
+Stops at the first error: the error of "ctx" if it is done before all values are emitted, an error wrapping
+ErrUnsorted if a source goes backwards, or the error "emit" returns.
+ */
+func MergeSorted(ctx context.Context, emit func(value int) error, sources ...Iterator) error {
+    // The values, each with the index of the source it came from.
+    kq := NewKeyedQueue[int](nil)
+
+    advance := func(source int) {
+        if value, ok := sources[source].Next(); ok {
+            kq.Push(value, source)
+        }
+    }
+
+    for source := range sources {
+        advance(source)
+    }
+
+    for kq.Size() > 0 {
+        if err := ctx.Err(); err != nil {
+            return err
+        }
+
+        e := kq.Pop()
+        value, source := e.Key(), e.Value()
+        if err := emit(value); err != nil {
+            return err
+        }
+
+        advance(source)
+        if kq.Size() > 0 && kq.Peek().Key() < value {
+            return fmt.Errorf("%w: source %d went from %d to %d", ErrUnsorted, source, value, kq.Peek().Key())
+        }
+    }
+    return nil
+}
+
+
+/*
+Merge sorted slices into a new sorted slice. Returns what was merged so far along with the error, read MergeSorted().
+ */
+func MergeSortedSlices(ctx context.Context, slices ...[]int) ([]int, error) {
+    total := 0
+    sources := make([]Iterator, len(slices))
+    for i, values := range slices {
+        total += len(values)
+        sources[i] = NewSliceIterator(values)
+    }
+
+    merged := make([]int, 0, total)
+    err := MergeSorted(ctx, func(value int) error {
+        merged = append(merged, value)
+        return nil
+    }, sources...)
+    return merged, err
+}
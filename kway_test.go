@@ -0,0 +1,83 @@
+package BrodalOkasakiHeap
+
+
+import (
+    "context"
+    "errors"
+    "math/rand"
+    "sort"
+    "testing"
+)
+
+
+func Test_kway_random(t *testing.T) {
+    rng := rand.New(rand.NewSource(1))
+
+    for round:=0; round<20; round++ {
+        var slices [][]int
+        var expected []int
+        for k := rng.Intn(10); k > 0; k-- {
+            values := make([]int, rng.Intn(50))
+            for i := range values {
+                values[i] = rng.Intn(100)
+            }
+            sort.Ints(values)
+            slices = append(slices, values)
+            expected = append(expected, values...)
+        }
+        sort.Ints(expected)
+
+        merged, err := MergeSortedSlices(context.Background(), slices...)
+        if err != nil {t.Fatalf("round %d: unexpected error %v", round, err)}
+        if len(merged) != len(expected) {t.Fatalf("round %d: expected %d values, got %d", round, len(expected), len(merged))}
+        for i := range expected {
+            if merged[i] != expected[i] {t.Fatalf("round %d: expected %v, got %v", round, expected, merged)}
+        }
+    }
+}
+
+
+func Test_kway_empty(t *testing.T) {
+    merged, err := MergeSortedSlices(context.Background())
+    if err != nil || len(merged) != 0 {t.Errorf("expected nothing, got %v, %v", merged, err)}
+
+    merged, err = MergeSortedSlices(context.Background(), nil, []int{}, []int{1})
+    if err != nil || len(merged) != 1 || merged[0] != 1 {t.Errorf("expected [1], got %v, %v", merged, err)}
+}
+
+
+func Test_kway_unsorted(t *testing.T) {
+    merged, err := MergeSortedSlices(context.Background(), []int{1, 4}, []int{2, 5, 3})
+    if !errors.Is(err, ErrUnsorted) {t.Fatalf("expected ErrUnsorted, got %v", err)}
+    if len(merged) != 4 {t.Errorf("expected the values up to the unsorted one, got %v", merged)}
+}
+
+
+func Test_kway_cancel(t *testing.T) {
+    ctx, cancel := context.WithCancel(context.Background())
+
+    count := 0
+    err := MergeSorted(ctx, func(value int) error {
+        count += 1
+        if count == 3 {
+            cancel()
+        }
+        return nil
+    }, NewSliceIterator(interval(0, 10)), NewSliceIterator(interval(0, 10)))
+
+    if !errors.Is(err, context.Canceled) {t.Errorf("expected context.Canceled, got %v", err)}
+    if count != 3 {t.Errorf("expected merging to stop after 3 values, got %d", count)}
+}
+
+
+func Test_kway_emit_error(t *testing.T) {
+    stop := errors.New("stop")
+    err := MergeSorted(context.Background(), func(value int) error {
+        if value == 2 {
+            return stop
+        }
+        return nil
+    }, NewSliceIterator([]int{0, 2, 4}), NewSliceIterator([]int{1, 3}))
+
+    if err != stop {t.Errorf("expected the error of emit, got %v", err)}
+}
@@ -0,0 +1,65 @@
+package BrodalOkasakiHeap
+
+
+/*
+Which key a heap hands out first. Every comparison of the heap goes through "Order.before", so a max-heap is the very
+same structure with the comparisons turned around: the root holds the largest key and every node is not larger than
+its parent.
+ */
+type Order int
+
+const (
+    MinFirst	Order = iota	// Pop() returns the smallest key. This is what NewBOHeap() makes.
+    MaxFirst					// Pop() returns the largest key.
+)
+
+
+/*
+Create a new Brodal-Okasaki heap popping its keys in the given order.
+ */
+func NewOrderedBOHeap(order Order) *BOHeap {
+    bq := NewBOHeap()
+    bq.order = order
+    return bq
+}
+
+
+/*
+Create a new Brodal-Okasaki heap that pops its largest key first.
+ */
+func NewMaxBOHeap() *BOHeap {
+    return NewOrderedBOHeap(MaxFirst)
+}
+
+
+/*
+Return the order the heap pops its keys in.
+ */
+func (bq *BOHeap) Order() Order {
+    return bq.order
+}
+
+
+/*
+Report whether node "a" has to come out of the heap strictly before node "b". Equal keys are never before each other,
+read the comment in "BOHeap.insert" for why that matters. The exception is a stable heap, where the node inserted first
+comes out first. Sequence numbers are unique among stable nodes, even across merged heaps, so two of them are still
+never before each other.
+ */
+func (order Order) before(a *BONode, b *BONode) bool {
+    if a.value == b.value {
+        return a.seq < b.seq
+    }
+    return order.beforeKey(a.value, b.value)
+}
+
+
+/*
+Same as "before", for bare keys.
+ */
+func (order Order) beforeKey(a int, b int) bool {
+    if order == MaxFirst {
+        return a > b
+    }
+    return a < b
+}
@@ -0,0 +1,61 @@
+package BrodalOkasakiHeap
+
+
+import (
+    "testing"
+    "math/rand"
+)
+
+
+func Test_max_heapsort(t *testing.T) {
+    const SIZE = 500
+    rand.Seed(1)
+
+    heap := NewMaxBOHeap()
+    insert_mult(heap, shuffle(interval(0, SIZE)))
+
+    for i:=SIZE-1; i>=0; i-- {
+        if heap.Peek() != i {t.Fatalf("expected to peek %d, got %d", i, heap.Peek())}
+        if err := heap.Validate(); err != nil {t.Fatalf("%v", err)}
+        pval := heap.Pop()
+        if pval != i {t.Fatalf("expected %d, got %d", i, pval)}
+    }
+}
+
+
+func Test_max_merge(t *testing.T) {
+    const SIZE = 200
+    rand.Seed(1)
+
+    h1 := NewMaxBOHeap()
+    h2 := NewOrderedBOHeap(MaxFirst)
+    insert_mult(h1, shuffle(interval(0, SIZE)))
+    insert_mult(h2, shuffle(interval(SIZE, 2*SIZE)))
+
+    h1.Merge(h2)
+    if h1.Order() != MaxFirst {t.Errorf("merge changed the order")}
+
+    for i:=2*SIZE-1; i>=0; i-- {
+        pval := h1.Pop()
+        if pval != i {t.Fatalf("expected %d, got %d", i, pval)}
+    }
+}
+
+
+func Test_merge_mixed_orders(t *testing.T) {
+    defer func() {
+        if recover() == nil {t.Errorf("merging a max-heap into a min-heap did not panic")}
+    }()
+
+    NewBOHeap().Merge(NewMaxBOHeap())
+}
+
+
+func Test_validate_order(t *testing.T) {
+    heap := NewMaxBOHeap()
+    insert_mult(heap, []int{1, 2, 3})
+
+    // Break the heap by hand, a child larger than the root.
+    heap.root.children_head.value = 10
+    if heap.Validate() == nil {t.Errorf("child larger than the root of a max-heap went unnoticed")}
+}
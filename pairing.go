@@ -0,0 +1,231 @@
+package BrodalOkasakiHeap
+
+
+/*
+A pairing heap: a single heap ordered tree, where every operation but Pop() is a single link of two trees.
+
+    * Insert(), Merge() and Peek() take O(1) time.
+    * Pop() links the children of the root pairwise, then folds the pairs into one tree. O(logn) amortized.
+    * DecreaseKey() cuts the subtree off its parent and links it with the root. O(logn) amortized by the known bounds,
+      though it is o(logn) in practice.
+
+Its constants are small, which makes it the usual pick in practice even though its bounds are amortized.
+ */
+type PairingHeap struct {
+    root	*pairingNode
+    size	int
+}
+
+
+/*
+Children are kept in a list through "sibling". "prev" is the left sibling, or the parent for the first child, so a
+node can be cut off in O(1) time.
+ */
+type pairingNode struct {
+    key		int
+    child	*pairingNode
+    sibling	*pairingNode
+    prev	*pairingNode
+    gone	bool		// Set once the key left the heap, so a stale handle is caught.
+}
+
+
+func (pn *pairingNode) Value() int {
+    return pn.key
+}
+
+
+/*
+Create a new pairing heap.
+ */
+func NewPairingHeap() *PairingHeap {
+    return &PairingHeap{}
+}
+
+
+func (ph *PairingHeap) Insert(value int) {
+    ph.Push(value)
+}
+
+
+func (ph *PairingHeap) Push(value int) Handle {
+    node := &pairingNode{key: value}
+    ph.root = linkPairing(ph.root, node)
+    ph.size += 1
+    return node
+}
+
+
+func (ph *PairingHeap) Pop() int {
+    if ph.root == nil {
+        panic("PairingHeap: Pop() on an empty heap")
+    }
+
+    root := ph.root
+    ph.root = combinePairs(root.child)
+    ph.size -= 1
+
+    root.child = nil
+    root.gone = true
+    return root.key
+}
+
+
+func (ph *PairingHeap) Peek() int {
+    if ph.root == nil {
+        panic("PairingHeap: Peek() on an empty heap")
+    }
+    return ph.root.key
+}
+
+
+func (ph *PairingHeap) PeekHandle() Handle {
+    if ph.root == nil {
+        panic("PairingHeap: PeekHandle() on an empty heap")
+    }
+    return ph.root
+}
+
+
+func (ph *PairingHeap) Size() int {
+    return ph.size
+}
+
+
+/*
+"other" must be another *PairingHeap. Its nodes now belong to this heap, so it is left empty.
+ */
+func (ph *PairingHeap) Merge(pq PriorityQueue) {
+    other, ok := pq.(*PairingHeap)
+    if !ok {
+        panic("PairingHeap: can only merge with another *PairingHeap")
+    }
+    if other == ph {
+        panic("PairingHeap: can't merge a heap with itself")
+    }
+
+    ph.root = linkPairing(ph.root, other.root)
+    ph.size += other.size
+    other.root = nil
+    other.size = 0
+}
+
+
+func (ph *PairingHeap) DecreaseKey(h Handle, value int) {
+    node := ph.handleNode(h)
+    if value > node.key {
+        panic("PairingHeap: DecreaseKey() to a larger key")
+    }
+
+    node.key = value
+    if node != ph.root {
+        node.cut()
+        ph.root = linkPairing(ph.root, node)
+    }
+}
+
+
+/*
+Cut the subtree of the handle off, pop its root, and link what is left back. O(logn) amortized, like Pop().
+ */
+func (ph *PairingHeap) Delete(h Handle) {
+    node := ph.handleNode(h)
+    if node == ph.root {
+        ph.Pop()
+        return
+    }
+
+    node.cut()
+    ph.root = linkPairing(ph.root, combinePairs(node.child))
+    ph.size -= 1
+
+    node.child = nil
+    node.gone = true
+}
+
+
+func (ph *PairingHeap) handleNode(h Handle) *pairingNode {
+    node, ok := h.(*pairingNode)
+    if !ok || node.gone {
+        panic("PairingHeap: handle does not belong to a key of a pairing heap")
+    }
+    return node
+}
+
+
+/*
+Unlink a node that is not the root from its parent and siblings.
+ */
+func (pn *pairingNode) cut() {
+    if pn.prev.child == pn {
+        pn.prev.child = pn.sibling
+    } else {
+        pn.prev.sibling = pn.sibling
+    }
+    if pn.sibling != nil {
+        pn.sibling.prev = pn.prev
+    }
+    pn.prev = nil
+    pn.sibling = nil
+}
+
+
+/*
+Link two trees, the root with the larger key becoming the first child of the other. Either may be nil.
+ */
+func linkPairing(a *pairingNode, b *pairingNode) *pairingNode {
+    if a == nil {
+        return b
+    } else if b == nil {
+        return a
+    }
+    if b.key < a.key {
+        a, b = b, a
+    }
+
+    b.sibling = a.child
+    if a.child != nil {
+        a.child.prev = b
+    }
+    b.prev = a
+    a.child = b
+    return a
+}
+
+
+/*
+Link a list of siblings into a single tree with the two-pass method: link them pairwise left to right, then fold the
+pairs into one tree right to left.
+ */
+func combinePairs(first *pairingNode) *pairingNode {
+    // The first pass chains the pairs through "sibling" in reverse, so the second pass can walk them right to left.
+    var pairs *pairingNode
+    for first != nil {
+        a := first
+        b := a.sibling
+        first = nil
+        if b != nil {
+            first = b.sibling
+            b.sibling = nil
+            b.prev = nil
+        }
+        a.sibling = nil
+        a.prev = nil
+
+        pair := linkPairing(a, b)
+        pair.sibling = pairs
+        pairs = pair
+    }
+
+    var root *pairingNode
+    for pairs != nil {
+        next := pairs.sibling
+        pairs.sibling = nil
+        root = linkPairing(root, pairs)
+        pairs = next
+    }
+    if root != nil {
+        root.prev = nil
+    }
+    return root
+}
@@ -0,0 +1,47 @@
+package BrodalOkasakiHeap
+
+
+import "testing"
+
+
+func Test_pairing_stale_handle(t *testing.T) {
+    ph := NewPairingHeap()
+    handle := ph.Push(1)
+    ph.Push(2)
+    ph.Pop()
+
+    mustPanic(t, func() { ph.DecreaseKey(handle, 0) })
+    mustPanic(t, func() { ph.Delete(handle) })
+    if ph.Size() != 1 || ph.Peek() != 2 {t.Errorf("stale handle changed the heap")}
+}
+
+
+func Test_pairing_merge_self(t *testing.T) {
+    ph := NewPairingHeap()
+    insert_mult_pq(ph, interval(0, 10))
+
+    mustPanic(t, func() { ph.Merge(ph) })
+    if ph.Size() != 10 {t.Errorf("expected size 10, got %d", ph.Size())}
+    for i:=0; i<10; i++ {
+        if pval := ph.Pop(); pval != i {t.Fatalf("expected %d, got %d", i, pval)}
+    }
+}
+
+
+func Test_pairing_cut_links(t *testing.T) {
+    ph := NewPairingHeap()
+    handles := make([]Handle, 10)
+    for i := range handles {
+        handles[i] = ph.Push(i)
+    }
+
+    // All of 1..9 are children of 0. Cutting from the middle of the list must keep the siblings linked.
+    ph.DecreaseKey(handles[5], -5)
+    ph.Delete(handles[7])
+    ph.DecreaseKey(handles[9], -9)
+
+    expected := []int{-9, -5, 0, 1, 2, 3, 4, 6, 8}
+    for _, value := range expected {
+        if pval := ph.Pop(); pval != value {t.Fatalf("expected %d, got %d", value, pval)}
+    }
+}
@@ -0,0 +1,130 @@
+package BrodalOkasakiHeap
+
+
+/*
+Every Insert() allocates a node and every Pop() lets one go, which keeps the garbage collector busy under heavy churn.
+A pooled heap carves its nodes out of slabs, arrays of "slabSize" nodes allocated in one go, and keeps the nodes freed by
+Pop() on a free list for the following inserts.
+
+Free nodes are chained through their "rightsibling" field, since a free node has no siblings.
+ */
+type nodePool struct {
+    free		*BONode		// Head of the free list.
+    nfree		int			// Length of the free list.
+    maxfree		int			// Freed nodes beyond this amount are left to the garbage collector, 0 means no limit.
+
+    slab		[]BONode	// Unused part of the current slab.
+    slabsize	int
+}
+
+
+/*
+Create a new Brodal-Okasaki heap that recycles its nodes.
+
+"slabSize" nodes are allocated at a time. At most "maxFree" freed nodes are kept for reuse, 0 keeps all of them.
+ */
+func NewPooledBOHeap(slabSize int, maxFree int) *BOHeap {
+    if slabSize < 1 {
+        slabSize = 1
+    }
+
+    bq := NewBOHeap()
+    bq.pool = &nodePool {
+        maxfree: maxFree,
+        slabsize: slabSize,
+    }
+    return bq
+}
+
+
+/*
+Change the amount of freed nodes kept for reuse, 0 keeps all of them. Nodes already beyond the new limit are dropped.
+Does nothing for a heap that is not pooled.
+ */
+func (bq *BOHeap) SetPoolCap(maxFree int) {
+    if bq.pool == nil {
+        return
+    }
+
+    bq.pool.maxfree = maxFree
+    for maxFree > 0 && bq.pool.nfree > maxFree {
+        bq.pool.take()
+    }
+}
+
+
+/*
+Let go of every free node and of the rest of the current slab. A slab is given back to the runtime once none of its
+nodes are in use anymore.
+ */
+func (bq *BOHeap) ShrinkPool() {
+    if bq.pool == nil {
+        return
+    }
+
+    bq.pool.free = nil
+    bq.pool.nfree = 0
+    bq.pool.slab = nil
+}
+
+
+/*
+Get a node either from the pool, or the usual way when there is no pool.
+ */
+func (bq *BOHeap) newNode(value int) *BONode {
+    if bq.pool == nil {
+        return newBONode(value)
+    }
+    return bq.pool.get(value)
+}
+
+
+/*
+Hand a node that is no longer part of the heap back to the pool.
+ */
+func (bq *BOHeap) freeNode(bon *BONode) {
+    if bq.pool != nil {
+        bq.pool.put(bon)
+    }
+}
+
+
+func (np *nodePool) get(value int) *BONode {
+    var node *BONode
+
+    if np.free != nil {
+        node = np.take()
+    } else {
+        if len(np.slab) == 0 {
+            np.slab = make([]BONode, np.slabsize)
+        }
+        node = &np.slab[0]
+        np.slab = np.slab[1:]
+    }
+
+    *node = BONode{value: value}
+    return node
+}
+
+
+func (np *nodePool) put(bon *BONode) {
+    if np.maxfree > 0 && np.nfree >= np.maxfree {
+        return
+    }
+
+    // Clear the links so a free node doesn't keep other nodes alive.
+    *bon = BONode{rightsibling: np.free}
+    np.free = bon
+    np.nfree += 1
+}
+
+
+/*
+Pop a node off the free list.
+ */
+func (np *nodePool) take() *BONode {
+    node := np.free
+    np.free = node.rightsibling
+    np.nfree -= 1
+    return node
+}
@@ -0,0 +1,118 @@
+package BrodalOkasakiHeap
+
+
+import (
+    "fmt"
+    "math/rand"
+    "runtime"
+    "testing"
+)
+
+
+func Test_pool_heapsort(t *testing.T) {
+    const SIZE = 1000
+    rand.Seed(1)
+
+    heap := NewPooledBOHeap(16, 0)
+
+    for round:=0; round<3; round++ {
+        insert_mult(heap, shuffle(interval(0, SIZE)))
+        for i:=0; i<SIZE; i++ {
+            pval := heap.Pop()
+            if pval != i {t.Fatalf("round %d: expected %d, got %d", round, i, pval)}
+        }
+    }
+
+    // The nodes of the last two rounds came from the free list.
+    if heap.pool.nfree != SIZE {t.Errorf("expected %d free nodes, got %d", SIZE, heap.pool.nfree)}
+}
+
+
+func Test_pool_reuse(t *testing.T) {
+    heap := NewPooledBOHeap(4, 0)
+    heap.Insert(1)
+    heap.Insert(2)
+
+    node := heap.root.children_head
+    heap.Pop()
+
+    // The popped value was at the root, the node that held 2 was freed after moving 2 up.
+    if heap.pool.free != node {t.Fatalf("freed node is not on the free list")}
+
+    heap.Insert(3)
+    if heap.root.children_head != node || node.value != 3 {t.Errorf("freed node was not reused")}
+    if err := heap.Validate(); err != nil {t.Errorf("%v", err)}
+}
+
+
+func Test_pool_cap(t *testing.T) {
+    heap := NewPooledBOHeap(8, 10)
+    insert_mult(heap, interval(0, 100))
+    for heap.Size() > 0 {
+        heap.Pop()
+    }
+    if heap.pool.nfree != 10 {t.Errorf("expected the free list capped at 10, got %d", heap.pool.nfree)}
+
+    heap.SetPoolCap(3)
+    if heap.pool.nfree != 3 {t.Errorf("expected the free list cut to 3, got %d", heap.pool.nfree)}
+
+    heap.ShrinkPool()
+    if heap.pool.nfree != 0 || heap.pool.free != nil || heap.pool.slab != nil {t.Errorf("pool not emptied")}
+
+    insert_mult(heap, []int{2, 1})
+    if heap.Pop() != 1 || heap.Pop() != 2 {t.Errorf("heap broken after shrinking")}
+}
+
+
+func Test_pool_merge(t *testing.T) {
+    const SIZE = 500
+    rand.Seed(1)
+
+    h1 := NewPooledBOHeap(32, 0)
+    h2 := NewBOHeap()
+    insert_mult(h1, shuffle(interval(0, SIZE)))
+    insert_mult(h2, shuffle(interval(SIZE, 2*SIZE)))
+
+    h1.Merge(h2)
+    for i:=0; i<2*SIZE; i++ {
+        pval := h1.Pop()
+        if pval != i {t.Fatalf("expected %d, got %d", i, pval)}
+    }
+}
+
+
+/*
+Churn through a heap of steady size with and without a pool. Besides allocations, reports the garbage collections per
+million operations.
+ */
+func BenchmarkPoolChurn(b *testing.B) {
+    for _, size := range []int{1000, 100000} {
+        for _, pooled := range []bool{false, true} {
+            b.Run(fmt.Sprintf("pooled=%t/n=%d", pooled, size), func(b *testing.B) {
+                rng := rand.New(rand.NewSource(1))
+                heap := NewBOHeap()
+                if pooled {
+                    heap = NewPooledBOHeap(1024, 0)
+                }
+                for i:=0; i<size; i++ {
+                    heap.Insert(rng.Int())
+                }
+
+                var before, after runtime.MemStats
+                runtime.GC()
+                runtime.ReadMemStats(&before)
+
+                b.ReportAllocs()
+                b.ResetTimer()
+                for i:=0; i<b.N; i++ {
+                    heap.Insert(rng.Int())
+                    heap.Pop()
+                }
+                b.StopTimer()
+
+                runtime.ReadMemStats(&after)
+                b.ReportMetric(float64(after.NumGC-before.NumGC)*1e6/float64(b.N), "GCs/Mop")
+            })
+        }
+    }
+}
@@ -2,6 +2,8 @@ package BrodalOkasakiHeap
 
 /*
 Generic operations to be performed over heap structure.
+
+Pop() and Peek() panic when the pqueue is empty, check Size() first.
  */
 type PriorityQueue interface {
     Insert(int)				// Insert an element into the pqueue.
@@ -11,7 +13,28 @@ type PriorityQueue interface {
     Merge(PriorityQueue)	// Merge two of the same-type priority queue (this means you shouldn't attempt merging a binary heap with a binomial heap).
 
     // Brodal-Okasaki heaps does not support DecreaseKey() operation, but in the discussion section of the paper,
-    // the author mentions a few ideas on how it might be possible.
-    // DecreaseKey()
+    // the author mentions a few ideas on how it might be possible. Queues that support it implement
+    // AddressablePriorityQueue.
+}
+
+
+/*
+A key inside an addressable pqueue, returned by Push(). A handle stays valid until its key leaves the pqueue through
+Pop() or Delete(), and it moves along with its key when the pqueue is merged into another one.
+ */
+type Handle interface {
+    Value()	int				// The current key.
+}
+
+
+/*
+A pqueue whose keys can be changed after insertion. Handles must belong to the pqueue they are used with.
+ */
+type AddressablePriorityQueue interface {
+    PriorityQueue
+    Push(int)	Handle			// Insert an element, returning its handle.
+    PeekHandle()	Handle		// Return the handle of the topmost key, the one Pop() would remove.
+    DecreaseKey(Handle, int)	// Change the key of a handle to a key that is not larger, panics otherwise.
+    Delete(Handle)				// Remove the key of a handle from the pqueue.
 }
 
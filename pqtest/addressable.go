@@ -0,0 +1,224 @@
+package pqtest
+
+import (
+    "math/rand"
+    "testing"
+
+    "github.com/cngkaygusuz/BrodalOkasakiHeap"
+)
+
+
+/*
+Run the suite for an addressable queue that pops its smallest key first: everything Run() does, then DecreaseKey() and
+Delete() through the handles.
+ */
+func RunAddressable(t *testing.T, newQueue func() BrodalOkasakiHeap.AddressablePriorityQueue) {
+    Run(t, func() BrodalOkasakiHeap.PriorityQueue { return newQueue() })
+
+    s := &addressableSuite{newQueue: newQueue}
+    t.Run("Handles", s.testHandles)
+    t.Run("DecreaseKey", s.testDecreaseKey)
+    t.Run("DecreaseKeyLarger", s.testDecreaseKeyLarger)
+    t.Run("Delete", s.testDelete)
+    t.Run("MergeHandles", s.testMergeHandles)
+    t.Run("RandomizedHandles", s.testRandomizedHandles)
+}
+
+
+type addressableSuite struct {
+    newQueue	func() BrodalOkasakiHeap.AddressablePriorityQueue
+}
+
+
+func pushAll(pq BrodalOkasakiHeap.AddressablePriorityQueue, values []int) []BrodalOkasakiHeap.Handle {
+    handles := make([]BrodalOkasakiHeap.Handle, len(values))
+    for i, value := range values {
+        handles[i] = pq.Push(value)
+    }
+    return handles
+}
+
+
+func (s *addressableSuite) testHandles(t *testing.T) {
+    pq := s.newQueue()
+    handles := pushAll(pq, []int{5, 3, 8})
+
+    for i, value := range []int{5, 3, 8} {
+        if handles[i].Value() != value {
+            t.Fatalf("handle %d: expected key %d, got %d", i, value, handles[i].Value())
+        }
+    }
+    if pq.Size() != 3 {
+        t.Fatalf("expected size 3, got %d", pq.Size())
+    }
+    if pq.PeekHandle() != handles[1] {
+        t.Fatalf("PeekHandle() is not the handle of the smallest key")
+    }
+    mustPanic(t, "PeekHandle() on an empty queue", func() { s.newQueue().PeekHandle() })
+}
+
+
+func (s *addressableSuite) testDecreaseKey(t *testing.T) {
+    pq := s.newQueue()
+    handles := pushAll(pq, Interval(0, 100))
+
+    // Every other key goes below all the others, in reverse order. Pop() first so that the keys sit in trees rather
+    // than in a freshly inserted list.
+    pq.Pop()
+    for i := 98; i > 0; i -= 2 {
+        pq.DecreaseKey(handles[i], -i)
+        if handles[i].Value() != -i {
+            t.Fatalf("expected key %d after DecreaseKey(), got %d", -i, handles[i].Value())
+        }
+        if pq.Peek() != -98 {
+            t.Fatalf("expected to peek -98, got %d", pq.Peek())
+        }
+    }
+    // Decreasing to the same key is allowed.
+    pq.DecreaseKey(handles[1], 1)
+
+    var expected []int
+    for i := 98; i > 0; i -= 2 {
+        expected = append(expected, -i)
+    }
+    for i := 1; i < 100; i += 2 {
+        expected = append(expected, i)
+    }
+    if popped := PopAll(pq); !equal(popped, expected) {
+        t.Fatalf("expected %v, got %v", expected, popped)
+    }
+}
+
+
+func (s *addressableSuite) testDecreaseKeyLarger(t *testing.T) {
+    pq := s.newQueue()
+    handle := pq.Push(5)
+    mustPanic(t, "DecreaseKey() to a larger key", func() { pq.DecreaseKey(handle, 6) })
+}
+
+
+func (s *addressableSuite) testDelete(t *testing.T) {
+    pq := s.newQueue()
+    handles := pushAll(pq, Interval(0, 100))
+    pq.Pop()
+
+    // Delete the multiples of 3, the current top included.
+    var expected []int
+    for i := 1; i < 100; i++ {
+        if i % 3 == 0 {
+            pq.Delete(handles[i])
+        } else {
+            expected = append(expected, i)
+        }
+    }
+    pq.Delete(handles[1])
+    expected = expected[1:]
+
+    if pq.Size() != len(expected) {
+        t.Fatalf("expected size %d, got %d", len(expected), pq.Size())
+    }
+    if popped := PopAll(pq); !equal(popped, expected) {
+        t.Fatalf("expected %v, got %v", expected, popped)
+    }
+}
+
+
+func (s *addressableSuite) testMergeHandles(t *testing.T) {
+    pq1 := s.newQueue()
+    pq2 := s.newQueue()
+    pushAll(pq1, Interval(0, 50))
+    handles := pushAll(pq2, Interval(50, 100))
+    pq1.Merge(pq2)
+
+    // Handles of the merged queue are used with the queue that took its keys.
+    pq1.DecreaseKey(handles[49], -1)
+    pq1.Delete(handles[0])
+
+    expected := append([]int{-1}, Interval(0, 50)...)
+    expected = append(expected, Interval(51, 99)...)
+    if popped := PopAll(pq1); !equal(popped, expected) {
+        t.Fatalf("expected %v, got %v", expected, popped)
+    }
+}
+
+
+/*
+A long random mix of every operation, checked against a plain map of the keys. Keys are kept distinct, so we know which
+handle a popped key belonged to.
+ */
+func (s *addressableSuite) testRandomizedHandles(t *testing.T) {
+    rounds := 20000
+    if testing.Short() {
+        rounds = 2000
+    }
+    rng := rand.New(rand.NewSource(7))
+
+    pq := s.newQueue()
+    live := make(map[int]BrodalOkasakiHeap.Handle)
+    newKey := func(below int) int {
+        for {
+            key := below - 1 - rng.Intn(1 << 20)
+            if _, used := live[key]; !used {
+                return key
+            }
+        }
+    }
+    anyHandle := func() (int, BrodalOkasakiHeap.Handle) {
+        for key, handle := range live {
+            return key, handle
+        }
+        return 0, nil
+    }
+
+    for round := 0; round < rounds; round++ {
+        switch op := rng.Intn(10); {
+        case op < 4 || len(live) == 0:
+            key := newKey(1000000)
+            live[key] = pq.Push(key)
+        case op < 6:
+            minkey := 0
+            first := true
+            for key := range live {
+                if first || key < minkey {
+                    minkey, first = key, false
+                }
+            }
+            if top := pq.PeekHandle(); top != live[minkey] {
+                t.Fatalf("round %d: PeekHandle() is not the handle of %d", round, minkey)
+            }
+            if popped := pq.Pop(); popped != minkey {
+                t.Fatalf("round %d: expected to pop %d, got %d", round, minkey, popped)
+            }
+            delete(live, minkey)
+        case op < 8:
+            key, handle := anyHandle()
+            newkey := newKey(key)
+            pq.DecreaseKey(handle, newkey)
+            delete(live, key)
+            live[newkey] = handle
+        case op < 9:
+            key, handle := anyHandle()
+            pq.Delete(handle)
+            delete(live, key)
+        default:
+            other := s.newQueue()
+            for i := rng.Intn(10); i > 0; i-- {
+                key := newKey(1000000)
+                live[key] = other.Push(key)
+            }
+            pq.Merge(other)
+        }
+
+        if pq.Size() != len(live) {
+            t.Fatalf("round %d: expected size %d, got %d", round, len(live), pq.Size())
+        }
+    }
+
+    for pq.Size() > 0 {
+        popped := pq.Pop()
+        if _, ok := live[popped]; !ok {
+            t.Fatalf("popped %d, which is not in the queue", popped)
+        }
+        delete(live, popped)
+    }
+}
@@ -0,0 +1,388 @@
+/*
+Package pqtest is a conformance suite for implementations of BrodalOkasakiHeap.PriorityQueue.
+
+The same tests BOHeap runs against itself can be pointed at any other implementation:
+
+    func TestMyQueue(t *testing.T) {
+        pqtest.Run(t, func() BrodalOkasakiHeap.PriorityQueue {
+            return NewMyQueue()
+        })
+    }
+
+Every test creates its queues through the given constructor, and Merge() is only ever called with two queues made by
+the same constructor.
+ */
+package pqtest
+
+import (
+    "math/rand"
+    "sort"
+    "testing"
+
+    "github.com/cngkaygusuz/BrodalOkasakiHeap"
+)
+
+
+/*
+Run the suite for a queue that pops its smallest key first.
+ */
+func Run(t *testing.T, newQueue func() BrodalOkasakiHeap.PriorityQueue) {
+    RunOrdered(t, newQueue, func(a int, b int) bool { return a < b })
+}
+
+
+/*
+Run the suite for a queue whose Pop() order is given by "before": before(a, b) reports whether "a" must be popped
+ahead of "b". Keys for which neither comes before the other may pop in any order.
+ */
+func RunOrdered(t *testing.T, newQueue func() BrodalOkasakiHeap.PriorityQueue, before func(a int, b int) bool) {
+    s := &suite{newQueue: newQueue, before: before}
+
+    t.Run("Empty", s.testEmpty)
+    t.Run("Single", s.testSingle)
+    t.Run("Ascending", s.testAscending)
+    t.Run("Descending", s.testDescending)
+    t.Run("Shuffled", s.testShuffled)
+    t.Run("Duplicates", s.testDuplicates)
+    t.Run("Extremes", s.testExtremes)
+    t.Run("Interleaved", s.testInterleaved)
+    t.Run("Merge", s.testMerge)
+    t.Run("MergeEmpty", s.testMergeEmpty)
+    t.Run("MergeChain", s.testMergeChain)
+    t.Run("Randomized", s.testRandomized)
+}
+
+
+// ====== Helpers ======
+
+
+/*
+Return the integers within [start, end).
+ */
+func Interval(start int, end int) []int {
+    slice := make([]int, 0, end-start)
+
+    for i:=start; i<end; i++ {
+        slice = append(slice, i)
+    }
+    return slice
+}
+
+
+/*
+Return a shuffled copy of "slice".
+ */
+func Shuffle(rng *rand.Rand, slice []int) []int {
+    shuffled := make([]int, len(slice))
+    copy(shuffled, slice)
+
+    rng.Shuffle(len(shuffled), func(i int, j int) {
+        shuffled[i], shuffled[j] = shuffled[j], shuffled[i]
+    })
+    return shuffled
+}
+
+
+/*
+Insert every value into the queue.
+ */
+func InsertAll(pq BrodalOkasakiHeap.PriorityQueue, values []int) {
+    for _, value := range values {
+        pq.Insert(value)
+    }
+}
+
+
+/*
+Pop everything out of the queue, in order.
+ */
+func PopAll(pq BrodalOkasakiHeap.PriorityQueue) []int {
+    popped := make([]int, 0, pq.Size())
+    for pq.Size() > 0 {
+        popped = append(popped, pq.Pop())
+    }
+    return popped
+}
+
+
+// ====== Suite ======
+
+
+type suite struct {
+    newQueue	func() BrodalOkasakiHeap.PriorityQueue
+    before		func(a int, b int) bool
+}
+
+
+/*
+The order a correct queue pops "values" in.
+ */
+func (s *suite) sorted(values []int) []int {
+    expected := make([]int, len(values))
+    copy(expected, values)
+    sort.SliceStable(expected, func(i int, j int) bool { return s.before(expected[i], expected[j]) })
+    return expected
+}
+
+
+/*
+Pop the queue empty, checking Peek(), Pop() and Size() against "expected" along the way.
+ */
+func (s *suite) drain(t *testing.T, pq BrodalOkasakiHeap.PriorityQueue, expected []int) {
+    t.Helper()
+
+    for i, value := range expected {
+        if size := pq.Size(); size != len(expected)-i {
+            t.Fatalf("pop %d: expected size %d, got %d", i, len(expected)-i, size)
+        }
+        if peeked := pq.Peek(); peeked != value {
+            t.Fatalf("pop %d: expected to peek %d, got %d", i, value, peeked)
+        }
+        if popped := pq.Pop(); popped != value {
+            t.Fatalf("pop %d: expected %d, got %d", i, value, popped)
+        }
+    }
+    if pq.Size() != 0 {
+        t.Fatalf("expected an empty queue, got size %d", pq.Size())
+    }
+}
+
+
+func (s *suite) testEmpty(t *testing.T) {
+    pq := s.newQueue()
+    if pq.Size() != 0 {
+        t.Fatalf("new queue has size %d", pq.Size())
+    }
+
+    mustPanic(t, "Pop() on an empty queue", func() { pq.Pop() })
+    mustPanic(t, "Peek() on an empty queue", func() { pq.Peek() })
+
+    if pq.Size() != 0 {
+        t.Fatalf("size changed to %d by operations on an empty queue", pq.Size())
+    }
+
+    // The queue must still be usable, and usable again once emptied.
+    for round := 0; round < 2; round++ {
+        InsertAll(pq, []int{2, 1})
+        s.drain(t, pq, s.sorted([]int{2, 1}))
+    }
+}
+
+
+func (s *suite) testSingle(t *testing.T) {
+    pq := s.newQueue()
+    pq.Insert(42)
+    s.drain(t, pq, []int{42})
+}
+
+
+func (s *suite) testAscending(t *testing.T) {
+    values := Interval(0, 100)
+    pq := s.newQueue()
+    InsertAll(pq, values)
+    s.drain(t, pq, s.sorted(values))
+}
+
+
+func (s *suite) testDescending(t *testing.T) {
+    values := Interval(0, 100)
+    sort.Sort(sort.Reverse(sort.IntSlice(values)))
+
+    pq := s.newQueue()
+    InsertAll(pq, values)
+    s.drain(t, pq, s.sorted(values))
+}
+
+
+func (s *suite) testShuffled(t *testing.T) {
+    rng := rand.New(rand.NewSource(1))
+
+    for _, size := range []int{2, 3, 7, 25, 64, 500} {
+        values := Shuffle(rng, Interval(0, size))
+        pq := s.newQueue()
+        InsertAll(pq, values)
+        s.drain(t, pq, s.sorted(values))
+    }
+}
+
+
+func (s *suite) testDuplicates(t *testing.T) {
+    rng := rand.New(rand.NewSource(2))
+
+    pq := s.newQueue()
+    InsertAll(pq, []int{5, 5, 5, 5})
+    s.drain(t, pq, []int{5, 5, 5, 5})
+
+    values := make([]int, 300)
+    for i := range values {
+        values[i] = rng.Intn(10)
+    }
+    InsertAll(pq, values)
+    s.drain(t, pq, s.sorted(values))
+}
+
+
+func (s *suite) testExtremes(t *testing.T) {
+    const (
+        maxInt = int(^uint(0) >> 1)
+        minInt = -maxInt - 1
+    )
+    values := []int{0, maxInt, minInt, -1, 1, maxInt, minInt}
+
+    pq := s.newQueue()
+    InsertAll(pq, values)
+    s.drain(t, pq, s.sorted(values))
+}
+
+
+/*
+Pops mixed with inserts, checked against a plain sorted slice.
+ */
+func (s *suite) testInterleaved(t *testing.T) {
+    rng := rand.New(rand.NewSource(3))
+
+    pq := s.newQueue()
+    var model []int
+
+    for i := 0; i < 2000; i++ {
+        if len(model) > 0 && rng.Intn(3) == 0 {
+            expected := model[0]
+            model = model[1:]
+            if popped := pq.Pop(); popped != expected {
+                t.Fatalf("op %d: expected to pop %d, got %d", i, expected, popped)
+            }
+        } else {
+            value := rng.Intn(1000)
+            pq.Insert(value)
+            model = s.sorted(append(model, value))
+        }
+
+        if pq.Size() != len(model) {
+            t.Fatalf("op %d: expected size %d, got %d", i, len(model), pq.Size())
+        }
+        if len(model) > 0 && pq.Peek() != model[0] {
+            t.Fatalf("op %d: expected to peek %d, got %d", i, model[0], pq.Peek())
+        }
+    }
+    s.drain(t, pq, model)
+}
+
+
+func (s *suite) testMerge(t *testing.T) {
+    rng := rand.New(rand.NewSource(4))
+
+    // Disjoint ranges, in both directions, and overlapping ranges.
+    ranges := [][2][]int{
+        {Interval(0, 50), Interval(50, 100)},
+        {Interval(50, 100), Interval(0, 50)},
+        {Shuffle(rng, Interval(0, 80)), Shuffle(rng, Interval(40, 120))},
+    }
+
+    for _, r := range ranges {
+        pq1 := s.newQueue()
+        pq2 := s.newQueue()
+        InsertAll(pq1, r[0])
+        InsertAll(pq2, r[1])
+
+        pq1.Merge(pq2)
+        s.drain(t, pq1, s.sorted(append(append([]int{}, r[0]...), r[1]...)))
+    }
+}
+
+
+func (s *suite) testMergeEmpty(t *testing.T) {
+    pq := s.newQueue()
+    InsertAll(pq, []int{3, 1, 2})
+
+    pq.Merge(s.newQueue())
+    s.drain(t, pq, s.sorted([]int{3, 1, 2}))
+
+    empty := s.newQueue()
+    full := s.newQueue()
+    InsertAll(full, []int{3, 1, 2})
+
+    empty.Merge(full)
+    s.drain(t, empty, s.sorted([]int{3, 1, 2}))
+}
+
+
+/*
+Queues that are merged into each other repeatedly, with pops in between, so merges pile up on top of merges.
+ */
+func (s *suite) testMergeChain(t *testing.T) {
+    rng := rand.New(rand.NewSource(5))
+
+    pq := s.newQueue()
+    var model []int
+
+    for round := 0; round < 30; round++ {
+        other := s.newQueue()
+        for i := rng.Intn(40); i > 0; i-- {
+            value := rng.Intn(500)
+            other.Insert(value)
+            model = append(model, value)
+        }
+        pq.Merge(other)
+        model = s.sorted(model)
+
+        for i := rng.Intn(15); i > 0 && len(model) > 0; i-- {
+            if popped := pq.Pop(); popped != model[0] {
+                t.Fatalf("round %d: expected to pop %d, got %d", round, model[0], popped)
+            }
+            model = model[1:]
+        }
+        if pq.Size() != len(model) {
+            t.Fatalf("round %d: expected size %d, got %d", round, len(model), pq.Size())
+        }
+    }
+    s.drain(t, pq, model)
+}
+
+
+func (s *suite) testRandomized(t *testing.T) {
+    size := 20000
+    if testing.Short() {
+        size = 2000
+    }
+    rng := rand.New(rand.NewSource(6))
+
+    values := make([]int, size)
+    for i := range values {
+        values[i] = rng.Int()
+    }
+
+    pq1 := s.newQueue()
+    pq2 := s.newQueue()
+    InsertAll(pq1, values[:size/2])
+    InsertAll(pq2, values[size/2:])
+    pq1.Merge(pq2)
+
+    if popped := PopAll(pq1); !equal(popped, s.sorted(values)) {
+        t.Fatalf("queue of %d random keys popped out of order", size)
+    }
+}
+
+
+func mustPanic(t *testing.T, what string, f func()) {
+    t.Helper()
+
+    defer func() {
+        if recover() == nil {
+            t.Errorf("%s did not panic", what)
+        }
+    }()
+    f()
+}
+
+
+func equal(a []int, b []int) bool {
+    if len(a) != len(b) {
+        return false
+    }
+    for i := range a {
+        if a[i] != b[i] {
+            return false
+        }
+    }
+    return true
+}
@@ -0,0 +1,142 @@
+package BrodalOkasakiHeap
+
+import (
+    "fmt"
+    "math/bits"
+)
+
+
+/*
+A tree of rank r holds at least 2^r nodes, so ranks stay well below 64.
+ */
+const maxRank = 64
+
+
+/*
+The root of a heap has every tree of the heap as its children, and insertion keeps asking it two questions: "is there a
+child of rank r?" and "where does a node of rank r go in the rank ordered list?". Walking the list answers both in O(logn)
+time, which makes binomial insertion cascades needlessly expensive.
+
+The rank index answers them in O(1). It remembers the first child of every rank, along with a bitmap of the ranks
+present, so finding the first child of rank >= r is a single bit scan. The children are still kept in the same doubly
+linked list, the index only points into it.
+
+Only roots carry an index. Every other node keeps walking its own (short) list in "putNodeAmongChildren".
+ */
+type rankIndex struct {
+    first	[maxRank]*BONode	// first[r] is the leftmost child of rank r.
+    present	uint64				// Bit r is set when there is a child of rank r.
+    tail	*BONode				// The rightmost child, where a node of the highest rank goes.
+}
+
+
+/*
+Make "root" the owner of the index, indexing the children it already has.
+ */
+func (ri *rankIndex) attach(root *BONode) {
+    for present := ri.present; present != 0; present &= present - 1 {
+        ri.first[bits.TrailingZeros64(present)] = nil
+    }
+    ri.present = 0
+    ri.tail = nil
+
+    for child := root.children_head; child != nil; child = child.rightsibling {
+        if ri.first[child.rank] == nil {
+            ri.first[child.rank] = child
+            ri.present |= 1 << uint(child.rank)
+        }
+        ri.tail = child
+    }
+    root.index = ri
+}
+
+
+/*
+Indexed version of "putNodeAmongChildren". The new node goes right before the first child of the same or higher rank,
+hence becomes the first child of its rank.
+ */
+func (ri *rankIndex) insert(root *BONode, other *BONode) {
+    rank := other.rank
+    higher := ri.present >> uint(rank) << uint(rank)
+
+    if higher == 0 {
+        // Our new node has the highest rank, or the list is empty.
+        other.leftsibling = ri.tail
+        if ri.tail == nil {
+            root.children_head = other
+        } else {
+            ri.tail.rightsibling = other
+        }
+        ri.tail = other
+    } else {
+        next := ri.first[bits.TrailingZeros64(higher)]
+        prev := next.leftsibling
+
+        other.leftsibling = prev
+        other.rightsibling = next
+        next.leftsibling = other
+        if prev == nil {
+            root.children_head = other
+        } else {
+            prev.rightsibling = other
+        }
+    }
+
+    ri.first[rank] = other
+    ri.present |= 1 << uint(rank)
+}
+
+
+/*
+Indexed version of the unlinking done by "rogue".
+ */
+func (ri *rankIndex) remove(root *BONode, bon *BONode) {
+    prev := bon.leftsibling
+    next := bon.rightsibling
+
+    if prev == nil {
+        root.children_head = next
+    } else {
+        prev.rightsibling = next
+    }
+    if next == nil {
+        ri.tail = prev
+    } else {
+        next.leftsibling = prev
+    }
+
+    if ri.first[bon.rank] == bon {
+        if next != nil && next.rank == bon.rank {
+            ri.first[bon.rank] = next
+        } else {
+            ri.first[bon.rank] = nil
+            ri.present &^= 1 << uint(bon.rank)
+        }
+    }
+}
+
+
+/*
+Check the index against the list it is supposed to describe. Used by "Validate".
+ */
+func (ri *rankIndex) validate(root *BONode) error {
+    var expected [maxRank]*BONode
+    var present uint64
+    var tail *BONode
+
+    for child := root.children_head; child != nil; child = child.rightsibling {
+        if child.rank < 0 || child.rank >= maxRank {
+            return fmt.Errorf("child %d has rank %d", child.value, child.rank)
+        }
+        if expected[child.rank] == nil {
+            expected[child.rank] = child
+            present |= 1 << uint(child.rank)
+        }
+        tail = child
+    }
+
+    if expected != ri.first || present != ri.present || tail != ri.tail {
+        return fmt.Errorf("rank index of root %d does not match its children", root.value)
+    }
+    return nil
+}
@@ -0,0 +1,70 @@
+package BrodalOkasakiHeap
+
+
+import (
+    "testing"
+    "math/rand"
+)
+
+
+func Test_rankindex_insert_order(t *testing.T) {
+    heap := NewBOHeap()
+    heap.insert(newBONode(0))
+
+    ranks := []int{3, 1, 4, 1, 0, 5, 2}
+    for i, rank := range ranks {
+        node := newBONode(100 + i)
+        node.rank = rank
+        heap.root.adopt(node)
+    }
+
+    if !rankOrdered(heap.root) {t.Errorf("not rank ordered")}
+    if err := heap.index.validate(heap.root); err != nil {t.Errorf("%v", err)}
+
+    // Both rank 1 nodes are there, the one adopted last comes first.
+    if heap.root.getSameRankChild(1).value != 103 {t.Errorf("wrong first child of rank 1")}
+    if heap.root.getSameRankChild(6) != nil {t.Errorf("found a child of a missing rank")}
+}
+
+
+func Test_rankindex_remove(t *testing.T) {
+    heap := NewBOHeap()
+    heap.insert(newBONode(0))
+
+    nodes := make([]*BONode, 0, 4)
+    for i, rank := range []int{1, 1, 2, 5} {
+        node := newBONode(100 + i)
+        node.rank = rank
+        heap.root.adopt(node)
+        nodes = append(nodes, node)
+    }
+
+    // Remove from the middle, the end and the head.
+    for _, i := range []int{0, 3, 2, 1} {
+        nodes[i].rogue()
+        if err := heap.index.validate(heap.root); err != nil {t.Fatalf("after removing %d: %v", nodes[i].value, err)}
+    }
+    if heap.root.hasChildren() {t.Errorf("children left behind")}
+}
+
+
+func Test_rankindex_workload(t *testing.T) {
+    const SIZE = 2000
+    rand.Seed(1)
+
+    h1 := NewBOHeap()
+    h2 := NewBOHeap()
+    insert_mult(h1, shuffle(interval(0, SIZE)))
+    insert_mult(h2, shuffle(interval(-SIZE, 0)))
+
+    for i:=0; i<SIZE/2; i++ {
+        h1.Pop()
+        h2.Pop()
+    }
+    h1.Merge(h2)
+
+    for h1.Size() > 0 {
+        if err := h1.Validate(); err != nil {t.Fatalf("%v", err)}
+        h1.Pop()
+    }
+}
@@ -0,0 +1,292 @@
+package BrodalOkasakiHeap
+
+import (
+    "bufio"
+    "encoding/binary"
+    "errors"
+    "fmt"
+    "io"
+)
+
+
+/*
+Operation logs let us reproduce a heap that went bad somewhere we can't attach a debugger to. A "Recorder" writes every
+public operation of the heaps it tracks, and "Replay" runs such a log against fresh heaps, checking every Pop() against
+the value that was popped when the log was written.
+
+The log is a stream of records preceded by a 5 byte header ("BOHR" and a version byte). Every record is an operation
+byte followed by varints:
+
+    'n' id              a new heap
+    'i' id value        Insert(value)
+    'p' id value        Pop() returned value
+    'm' id otherid      Merge() of heap "otherid" into heap "id"
+
+Heap ids are handed out by the recorder in creation order, starting from 0.
+ */
+const (
+    recordMagic		= "BOHR"
+    recordVersion	= 1
+
+    opNew		= 'n'
+    opInsert	= 'i'
+    opPop		= 'p'
+    opMerge		= 'm'
+)
+
+
+// Returned by "Recorder.Err" when a recorded heap merged a heap the recorder doesn't know about.
+var ErrNotRecorded = errors.New("merged heap is not tracked by the same recorder")
+
+
+/*
+Writes the operation log of every heap created through it.
+ */
+type Recorder struct {
+    w		io.Writer
+    buf		[]byte
+    nextid	int
+    err		error
+}
+
+
+/*
+Create a recorder writing to "w". Every operation is written as soon as it is done, so wrap "w" in a bufio.Writer if
+that is too many writes, and flush it when done.
+ */
+func NewRecorder(w io.Writer) *Recorder {
+    rec := &Recorder {
+        w: w,
+        buf: make([]byte, 0, 2*binary.MaxVarintLen64 + 1),
+    }
+
+    rec.buf = append(rec.buf, recordMagic...)
+    rec.buf = append(rec.buf, recordVersion)
+    rec.flush()
+
+    return rec
+}
+
+
+/*
+Create a new heap whose operations are recorded.
+ */
+func (rec *Recorder) NewBOHeap() *BOHeap {
+    bq := NewBOHeap()
+    bq.recorder = rec
+    bq.id = rec.recordNew()
+    return bq
+}
+
+
+/*
+Create a new compact heap in "arena" whose operations are recorded, a new arena if nil. Its log replays the same as the
+one of a BOHeap.
+ */
+func (rec *Recorder) NewCompactBOHeap(arena *CompactArena) *CompactBOHeap {
+    if arena == nil {
+        arena = NewCompactArena(0)
+    }
+    ch := arena.NewBOHeap(MinFirst)
+    ch.recorder = rec
+    ch.id = rec.recordNew()
+    return ch
+}
+
+
+/*
+Return the first error met while recording. Once there is an error, nothing more is written.
+ */
+func (rec *Recorder) Err() error {
+    return rec.err
+}
+
+
+/*
+Hand out the id of a new heap, and log it.
+ */
+func (rec *Recorder) recordNew() int {
+    id := rec.nextid
+    rec.nextid += 1
+
+    if rec.err == nil {
+        rec.start(opNew, id)
+        rec.flush()
+    }
+    return id
+}
+
+
+func (rec *Recorder) recordInsert(id int, value int) {
+    if rec.err == nil {
+        rec.start(opInsert, id)
+        rec.buf = binary.AppendVarint(rec.buf, int64(value))
+        rec.flush()
+    }
+}
+
+
+func (rec *Recorder) recordPop(id int, value int) {
+    if rec.err == nil {
+        rec.start(opPop, id)
+        rec.buf = binary.AppendVarint(rec.buf, int64(value))
+        rec.flush()
+    }
+}
+
+
+/*
+Log the merge of heap "otherid", recorded by "other", into heap "id".
+ */
+func (rec *Recorder) recordMerge(id int, other *Recorder, otherid int) {
+    if other != rec {
+        // We have no idea what the other heap holds, so the log can't be replayed from here on.
+        rec.err = ErrNotRecorded
+    }
+    if rec.err == nil {
+        rec.start(opMerge, id)
+        rec.buf = binary.AppendUvarint(rec.buf, uint64(otherid))
+        rec.flush()
+    }
+}
+
+
+/*
+Begin a new record in the buffer.
+ */
+func (rec *Recorder) start(op byte, id int) {
+    rec.buf = append(rec.buf[:0], op)
+    rec.buf = binary.AppendUvarint(rec.buf, uint64(id))
+}
+
+
+/*
+Write out the buffer.
+ */
+func (rec *Recorder) flush() {
+    _, rec.err = rec.w.Write(rec.buf)
+}
+
+
+// ====== Replay ======
+
+
+/*
+The first place where a replayed log stopped agreeing with the recording.
+ */
+type Divergence struct {
+    Op			int		// Index of the operation within the log, the first record after the header is 0.
+    Heap		int		// Id of the heap the operation was performed on.
+    Expected	int		// The value popped while recording.
+    Got			int		// The value popped while replaying.
+    Empty		bool	// The replayed heap was empty, so there was nothing to pop.
+}
+
+
+func (dv *Divergence) Error() string {
+    if dv.Empty {
+        return fmt.Sprintf("op %d: heap %d is empty, expected to pop %d", dv.Op, dv.Heap, dv.Expected)
+    }
+    return fmt.Sprintf("op %d: heap %d popped %d, expected %d", dv.Op, dv.Heap, dv.Got, dv.Expected)
+}
+
+
+/*
+Re-execute a log written by a "Recorder" against fresh heaps.
+
+Returns nil if every Pop() returned the recorded value, a *Divergence for the first one that didn't, or another error
+if the log itself is broken.
+ */
+func Replay(r io.Reader) error {
+    br := bufio.NewReader(r)
+
+    header := make([]byte, len(recordMagic)+1)
+    if _, err := io.ReadFull(br, header); err != nil {
+        return fmt.Errorf("reading header: %w", err)
+    }
+    if string(header[:len(recordMagic)]) != recordMagic || header[len(recordMagic)] != recordVersion {
+        return errors.New("not a heap operation log")
+    }
+
+    heaps := make(map[int]*BOHeap)
+
+    for opindex := 0; ; opindex++ {
+        op, err := br.ReadByte()
+        if err == io.EOF {
+            return nil
+        } else if err != nil {
+            return err
+        }
+
+        id, err := readUvarint(br)
+        if err != nil {
+            return fmt.Errorf("op %d: %w", opindex, err)
+        }
+
+        if op == opNew {
+            heaps[id] = NewBOHeap()
+            continue
+        }
+
+        bq := heaps[id]
+        if bq == nil {
+            return fmt.Errorf("op %d: unknown heap %d", opindex, id)
+        }
+
+        switch op {
+        case opInsert:
+            value, err := readVarint(br)
+            if err != nil {
+                return fmt.Errorf("op %d: %w", opindex, err)
+            }
+            bq.Insert(value)
+
+        case opPop:
+            value, err := readVarint(br)
+            if err != nil {
+                return fmt.Errorf("op %d: %w", opindex, err)
+            }
+            if bq.Size() == 0 {
+                return &Divergence{Op: opindex, Heap: id, Expected: value, Empty: true}
+            }
+            if got := bq.Pop(); got != value {
+                return &Divergence{Op: opindex, Heap: id, Expected: value, Got: got}
+            }
+
+        case opMerge:
+            otherid, err := readUvarint(br)
+            if err != nil {
+                return fmt.Errorf("op %d: %w", opindex, err)
+            }
+            other := heaps[otherid]
+            if other == nil {
+                return fmt.Errorf("op %d: unknown heap %d", opindex, otherid)
+            }
+            bq.Merge(other)
+
+        default:
+            return fmt.Errorf("op %d: unknown operation %q", opindex, op)
+        }
+    }
+}
+
+
+/*
+Read the arguments of a record. The log may only end between records, so running out of input here is an error.
+ */
+func readUvarint(br *bufio.Reader) (int, error) {
+    value, err := binary.ReadUvarint(br)
+    if err == io.EOF {
+        err = io.ErrUnexpectedEOF
+    }
+    return int(value), err
+}
+
+
+func readVarint(br *bufio.Reader) (int, error) {
+    value, err := binary.ReadVarint(br)
+    if err == io.EOF {
+        err = io.ErrUnexpectedEOF
+    }
+    return int(value), err
+}
@@ -0,0 +1,91 @@
+package BrodalOkasakiHeap
+
+
+import (
+    "bytes"
+    "errors"
+    "io"
+    "math/rand"
+    "testing"
+)
+
+
+func Test_record_replay(t *testing.T) {
+    rand.Seed(1)
+    var log bytes.Buffer
+    rec := NewRecorder(&log)
+
+    h1 := rec.NewBOHeap()
+    h2 := rec.NewBOHeap()
+    insert_mult(h1, shuffle(interval(0, 100)))
+    insert_mult(h2, shuffle(interval(-50, 50)))
+
+    for i:=0; i<10; i++ {
+        h1.Pop()
+    }
+    h1.Merge(h2)
+    for h1.Size() > 0 {
+        h1.Pop()
+    }
+
+    if rec.Err() != nil {t.Fatalf("recording failed: %v", rec.Err())}
+    if err := Replay(&log); err != nil {t.Errorf("replay failed: %v", err)}
+}
+
+
+func Test_replay_divergence(t *testing.T) {
+    var log bytes.Buffer
+    rec := NewRecorder(&log)
+
+    heap := rec.NewBOHeap()
+    insert_mult(heap, []int{3, 1, 2})
+    heap.Pop()
+    // Pretend the second Pop() returned something else back then.
+    rec.recordPop(heap.id, 7)
+
+    err := Replay(&log)
+    var dv *Divergence
+    if !errors.As(err, &dv) {t.Fatalf("expected a divergence, got %v", err)}
+    if dv.Op != 5 || dv.Heap != 0 || dv.Expected != 7 || dv.Got != 2 {t.Errorf("wrong divergence %+v", dv)}
+}
+
+
+func Test_replay_empty(t *testing.T) {
+    var log bytes.Buffer
+    rec := NewRecorder(&log)
+
+    heap := rec.NewBOHeap()
+    rec.recordPop(heap.id, 1)
+
+    var dv *Divergence
+    if err := Replay(&log); !errors.As(err, &dv) || !dv.Empty {t.Errorf("expected an empty heap divergence, got %v", err)}
+}
+
+
+func Test_replay_truncated(t *testing.T) {
+    var log bytes.Buffer
+    rec := NewRecorder(&log)
+
+    heap := rec.NewBOHeap()
+    heap.Insert(1 << 30)
+
+    truncated := log.Bytes()[:log.Len()-1]
+    if err := Replay(bytes.NewReader(truncated)); !errors.Is(err, io.ErrUnexpectedEOF) {t.Errorf("expected unexpected EOF, got %v", err)}
+
+    if err := Replay(bytes.NewReader([]byte("not a log"))); err == nil {t.Errorf("accepted a bad header")}
+}
+
+
+func Test_record_untracked_merge(t *testing.T) {
+    var log bytes.Buffer
+    rec := NewRecorder(&log)
+
+    heap := rec.NewBOHeap()
+    heap.Insert(1)
+
+    other := NewBOHeap()
+    other.Insert(2)
+    heap.Merge(other)
+
+    if rec.Err() != ErrNotRecorded {t.Errorf("expected ErrNotRecorded, got %v", rec.Err())}
+}
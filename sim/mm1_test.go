@@ -0,0 +1,104 @@
+package sim
+
+
+import (
+    "math"
+    "math/rand"
+    "testing"
+)
+
+
+/*
+The M/M/1 queue: customers arrive one by one with exponential interarrival times, and a single server serves them first
+come first served, in exponential service times. Time is in milliseconds.
+ */
+type mm1 struct {
+    sim			*Simulator
+    rng			*rand.Rand
+    arrival		float64		// Mean interarrival time.
+    service		float64		// Mean service time.
+
+    queue		[]int		// Arrival times of the waiting customers, the one in service first.
+    served		int
+    limit		int			// Stop arriving after this many customers.
+
+    totalTime	int			// Sum of the times in system of the served customers.
+    busyTime	int
+}
+
+
+func (m *mm1) exponential(mean float64) int {
+    return int(math.Round(m.rng.ExpFloat64() * mean))
+}
+
+
+func (m *mm1) arrive() {
+    m.queue = append(m.queue, m.sim.Now())
+    if len(m.queue) == 1 {
+        m.startService()
+    }
+
+    m.limit -= 1
+    if m.limit > 0 {
+        m.sim.Schedule(m.exponential(m.arrival), m.arrive)
+    }
+}
+
+
+func (m *mm1) startService() {
+    duration := m.exponential(m.service)
+    m.busyTime += duration
+    m.sim.Schedule(duration, m.depart)
+}
+
+
+func (m *mm1) depart() {
+    m.totalTime += m.sim.Now() - m.queue[0]
+    m.served += 1
+    m.queue = m.queue[1:]
+    if len(m.queue) > 0 {
+        m.startService()
+    }
+}
+
+
+func runMM1(seed int64, arrival float64, service float64, customers int) *mm1 {
+    m := &mm1 {
+        sim: NewSimulator(),
+        rng: rand.New(rand.NewSource(seed)),
+        arrival: arrival,
+        service: service,
+        limit: customers,
+    }
+    m.sim.Schedule(m.exponential(arrival), m.arrive)
+    m.sim.Run()
+    return m
+}
+
+
+func Test_sim_mm1(t *testing.T) {
+    const (
+        ARRIVAL = 2000.0
+        SERVICE = 1000.0
+        CUSTOMERS = 200000
+    )
+    m := runMM1(1, ARRIVAL, SERVICE, CUSTOMERS)
+
+    if m.served != CUSTOMERS {t.Fatalf("expected %d customers served, got %d", CUSTOMERS, m.served)}
+
+    // Utilization is service/arrival, and the mean time in system is 1/(mu-lambda).
+    rho := SERVICE / ARRIVAL
+    utilization := float64(m.busyTime) / float64(m.sim.Now())
+    if math.Abs(utilization - rho) > 0.02 {t.Errorf("expected utilization %.3f, got %.3f", rho, utilization)}
+
+    expected := 1 / (1/SERVICE - 1/ARRIVAL)
+    mean := float64(m.totalTime) / float64(m.served)
+    if math.Abs(mean - expected) / expected > 0.05 {t.Errorf("expected mean time in system %.0f, got %.0f", expected, mean)}
+}
+
+
+func Test_sim_mm1_deterministic(t *testing.T) {
+    m1 := runMM1(7, 1000, 900, 5000)
+    m2 := runMM1(7, 1000, 900, 5000)
+    if m1.totalTime != m2.totalTime || m1.sim.Now() != m2.sim.Now() {t.Errorf("same seed gave different runs")}
+}
@@ -0,0 +1,176 @@
+/*
+Package sim is a small discrete-event simulation kernel, its future-event list being a BOHeap keyed by virtual time.
+
+Virtual time is an int, in whatever unit the model likes. Events are actions scheduled at a point in time, and running
+the simulation fires them in order of time, moving the clock forward to every one of them:
+
+    s := sim.NewSimulator()
+    s.Schedule(5, func() { fmt.Println("at", s.Now()) })
+    s.Run()  // at 5
+
+Events at the same time fire in the order they were scheduled, so a model run twice with the same random numbers does
+the same thing twice.
+ */
+package sim
+
+import (
+    "github.com/cngkaygusuz/BrodalOkasakiHeap"
+)
+
+
+/*
+A scheduled event, the handle to cancel it with.
+ */
+type Event struct {
+    entry		*BrodalOkasakiHeap.KeyedEntry[*Event]
+    action		func()
+}
+
+
+/*
+The time the event fires at.
+ */
+func (e *Event) Time() int {
+    return e.entry.Key()
+}
+
+
+/*
+Whether the event is still waiting to fire.
+ */
+func (e *Event) Pending() bool {
+    return e.entry.Queued()
+}
+
+
+/*
+The simulation clock and the future-event list, a KeyedQueue on a BOHeap keyed by the time of the events. Events of
+the same time come out in the order they were scheduled, which is what makes ties deterministic.
+ */
+type Simulator struct {
+    now			int
+    events		*BrodalOkasakiHeap.KeyedQueue[*Event]
+    stopped		bool
+}
+
+
+/*
+Create a new simulator, its clock at 0.
+ */
+func NewSimulator() *Simulator {
+    return &Simulator {
+        events: BrodalOkasakiHeap.NewKeyedQueue[*Event](nil),
+    }
+}
+
+
+/*
+The current virtual time.
+ */
+func (s *Simulator) Now() int {
+    return s.now
+}
+
+
+/*
+The amount of events waiting to fire, not counting the cancelled ones.
+ */
+func (s *Simulator) Pending() int {
+    return s.events.Size()
+}
+
+
+/*
+Schedule "action" to fire "delay" time units from now. Panics if "delay" is negative.
+ */
+func (s *Simulator) Schedule(delay int, action func()) *Event {
+    if delay < 0 {
+        panic("sim: scheduling an event in the past")
+    }
+    return s.ScheduleAt(s.now + delay, action)
+}
+
+
+/*
+Schedule "action" to fire at time "at". Panics if "at" is in the past; an event scheduled for now fires after the ones
+already scheduled for now.
+ */
+func (s *Simulator) ScheduleAt(at int, action func()) *Event {
+    if at < s.now {
+        panic("sim: scheduling an event in the past")
+    }
+
+    e := &Event{action: action}
+    e.entry = s.events.Push(at, e)
+    return e
+}
+
+
+/*
+Cancel an event that has not fired yet. Returns false if it has already fired or been cancelled.
+
+The cancelled event leaves the future-event list once its time comes, or sooner when cancelled events come to outnumber
+the pending ones, so cancelling a far away timeout over and over doesn't pile up events.
+ */
+func (s *Simulator) Cancel(e *Event) bool {
+    return s.events.Remove(e.entry)
+}
+
+
+/*
+Fire the next event, moving the clock to its time. Returns false if there is none.
+ */
+func (s *Simulator) Step() bool {
+    if s.events.Size() == 0 {
+        return false
+    }
+    s.fire()
+    return true
+}
+
+
+/*
+Fire events until there are none left, or until Stop() is called by one of them.
+ */
+func (s *Simulator) Run() {
+    s.stopped = false
+    for !s.stopped && s.Step() {
+    }
+}
+
+
+/*
+Fire the events up to and including time "until", then move the clock to "until". Stop() ends the run early, leaving
+the clock at the time of the last fired event. Panics if "until" is in the past.
+ */
+func (s *Simulator) RunUntil(until int) {
+    if until < s.now {
+        panic("sim: running until a time in the past")
+    }
+
+    s.stopped = false
+    for !s.stopped && s.events.Size() > 0 && s.events.Peek().Key() <= until {
+        s.fire()
+    }
+    if !s.stopped {
+        s.now = until
+    }
+}
+
+
+/*
+Stop the running Run() or RunUntil() once the current event is done.
+ */
+func (s *Simulator) Stop() {
+    s.stopped = true
+}
+
+
+/*
+Pop the next event and fire it.
+ */
+func (s *Simulator) fire() {
+    entry := s.events.Pop()
+    s.now = entry.Key()
+    entry.Value().action()
+}
@@ -0,0 +1,153 @@
+package sim
+
+
+import (
+    "runtime"
+    "testing"
+)
+
+
+func Test_sim_order(t *testing.T) {
+    s := NewSimulator()
+    var fired []int
+    var times []int
+    record := func(id int) func() {
+        return func() {
+            fired = append(fired, id)
+            times = append(times, s.Now())
+        }
+    }
+
+    s.Schedule(5, record(0))
+    s.Schedule(1, record(1))
+    s.Schedule(5, record(2))
+    s.ScheduleAt(3, func() {
+        record(3)()
+        // Scheduled for now, fires after everything already at 3.
+        s.Schedule(0, record(4))
+    })
+    s.Schedule(3, record(5))
+    s.Run()
+
+    expected := []int{1, 3, 5, 4, 0, 2}
+    expectedTimes := []int{1, 3, 3, 3, 5, 5}
+    if len(fired) != len(expected) {t.Fatalf("expected %v, got %v", expected, fired)}
+    for i := range expected {
+        if fired[i] != expected[i] || times[i] != expectedTimes[i] {t.Fatalf("expected %v at %v, got %v at %v", expected, expectedTimes, fired, times)}
+    }
+    if s.Now() != 5 {t.Errorf("expected the clock at 5, got %d", s.Now())}
+    if s.Pending() != 0 {t.Errorf("expected no pending events, got %d", s.Pending())}
+}
+
+
+func Test_sim_cancel(t *testing.T) {
+    s := NewSimulator()
+    var fired []int
+
+    e1 := s.Schedule(1, func() { fired = append(fired, 1) })
+    e2 := s.Schedule(2, func() { fired = append(fired, 2) })
+    s.Schedule(2, func() { fired = append(fired, 3) })
+    if s.Pending() != 3 {t.Errorf("expected 3 pending events, got %d", s.Pending())}
+
+    if !s.Cancel(e2) {t.Errorf("cancelling a pending event failed")}
+    if s.Cancel(e2) {t.Errorf("cancelled an event twice")}
+    if e2.Pending() {t.Errorf("cancelled event is pending")}
+    if s.Pending() != 2 {t.Errorf("expected 2 pending events, got %d", s.Pending())}
+
+    s.Run()
+    if len(fired) != 2 || fired[0] != 1 || fired[1] != 3 {t.Errorf("expected [1 3], got %v", fired)}
+    if s.Cancel(e1) {t.Errorf("cancelled an event that has fired")}
+}
+
+
+func Test_sim_run_until(t *testing.T) {
+    s := NewSimulator()
+    count := 0
+    var tick func()
+    tick = func() {
+        count += 1
+        s.Schedule(10, tick)
+    }
+    s.Schedule(0, tick)
+
+    s.RunUntil(35)
+    if count != 4 {t.Errorf("expected 4 ticks, got %d", count)}
+    if s.Now() != 35 {t.Errorf("expected the clock at 35, got %d", s.Now())}
+
+    s.RunUntil(40)
+    if count != 5 {t.Errorf("expected 5 ticks, got %d", count)}
+    if s.Pending() != 1 {t.Errorf("expected the next tick pending, got %d", s.Pending())}
+}
+
+
+func Test_sim_stop(t *testing.T) {
+    s := NewSimulator()
+    count := 0
+    for i:=1; i<=10; i++ {
+        s.Schedule(i, func() {
+            count += 1
+            if count == 3 {
+                s.Stop()
+            }
+        })
+    }
+
+    s.Run()
+    if count != 3 || s.Now() != 3 {t.Errorf("expected to stop after 3 events at 3, got %d at %d", count, s.Now())}
+    s.Run()
+    if count != 10 {t.Errorf("expected to resume, got %d events", count)}
+}
+
+
+func Test_sim_past(t *testing.T) {
+    s := NewSimulator()
+    s.RunUntil(10)
+
+    for _, f := range []func(){
+        func() { s.Schedule(-1, func() {}) },
+        func() { s.ScheduleAt(9, func() {}) },
+        func() { s.RunUntil(9) },
+    } {
+        func() {
+            defer func() {
+                if recover() == nil {t.Errorf("going back in time did not panic")}
+            }()
+            f()
+        }()
+    }
+}
+
+
+func Test_sim_cancelled_timeouts(t *testing.T) {
+    const STEPS = 200000
+    s := NewSimulator()
+
+    // Every step reschedules a timeout far ahead, cancelling the previous one.
+    var before, after runtime.MemStats
+    runtime.GC()
+    runtime.ReadMemStats(&before)
+
+    steps, timeouts := 0, 0
+    var timeout *Event
+    var step func()
+    step = func() {
+        steps += 1
+        if timeout != nil {
+            s.Cancel(timeout)
+        }
+        timeout = s.Schedule(1000000000, func() { timeouts += 1 })
+        if steps < STEPS {
+            s.Schedule(1, step)
+        }
+    }
+    s.Schedule(0, step)
+    s.RunUntil(STEPS)
+
+    runtime.GC()
+    runtime.ReadMemStats(&after)
+    if steps != STEPS || s.Pending() != 1 {t.Fatalf("expected %d steps and the last timeout pending, got %d and %d", STEPS, steps, s.Pending())}
+    if grown := int64(after.HeapAlloc) - int64(before.HeapAlloc); grown > 4 << 20 {t.Errorf("cancelled events kept around, heap grew by %d bytes", grown)}
+
+    s.Run()
+    if timeouts != 1 {t.Errorf("expected only the last timeout to fire, got %d", timeouts)}
+}
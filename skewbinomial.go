@@ -0,0 +1,86 @@
+package BrodalOkasakiHeap
+
+
+/*
+A plain skew-binomial heap, the structure Brodal and Okasaki start from before bootstrapping it. It is a forest of
+skew-binomial trees, kept as the rank ordered children of a header node that holds no key itself. Only the two trees of
+the smallest rank may share a rank.
+
+Compared to BOHeap there is no global root, so:
+    * Insert() is the same worst case O(1) skew-linking, "BOHeap.insert_skew".
+    * Peek() scans the trees for the minimum, O(logn).
+    * Merge() links the trees of both heaps like adding two binary numbers, O(logn).
+    * Pop() is a Merge() of the children of the minimum into the rest of the forest, O(logn).
+ */
+type SkewBinomialHeap struct {
+    trees	BONode
+    size	int
+}
+
+
+/*
+Create a new skew-binomial heap.
+ */
+func NewSkewBinomialHeap() *SkewBinomialHeap {
+    return &SkewBinomialHeap{}
+}
+
+
+func (sh *SkewBinomialHeap) Insert(value int) {
+    node1, node2 := sh.trees.getSmallestRankChildren()
+    sh.trees.adopt(skewLink(MinFirst, node1, node2, newBONode(value)))
+    sh.size += 1
+}
+
+
+func (sh *SkewBinomialHeap) Pop() int {
+    if sh.size == 0 {
+        panic("SkewBinomialHeap: Pop() on an empty heap")
+    }
+
+    minnode := sh.trees.getMinChild(MinFirst)
+    minnode.rogue()
+    meldForest(&sh.trees, minnode, MinFirst)
+    sh.size -= 1
+
+    return minnode.value
+}
+
+
+func (sh *SkewBinomialHeap) Peek() int {
+    if sh.size == 0 {
+        panic("SkewBinomialHeap: Peek() on an empty heap")
+    }
+    return sh.trees.getMinChild(MinFirst).value
+}
+
+
+func (sh *SkewBinomialHeap) Size() int {
+    return sh.size
+}
+
+
+/*
+"other" must be another *SkewBinomialHeap. Its trees now belong to this heap, so it is left empty.
+ */
+func (sh *SkewBinomialHeap) Merge(pq PriorityQueue) {
+    other, ok := pq.(*SkewBinomialHeap)
+    if !ok {
+        panic("SkewBinomialHeap: can only merge with another *SkewBinomialHeap")
+    }
+    if other == sh {
+        panic("SkewBinomialHeap: can't merge a heap with itself")
+    }
+
+    meldForest(&sh.trees, &other.trees, MinFirst)
+    sh.size += other.size
+    other.size = 0
+}
+
+
+/*
+Check the heap order within every tree, the links, the rank order of the forest and the size. Same as "BOHeap.Validate".
+ */
+func (sh *SkewBinomialHeap) Validate() error {
+    return validateForest(&sh.trees, sh.size, true)
+}
@@ -0,0 +1,77 @@
+package BrodalOkasakiHeap
+
+
+import (
+    "testing"
+    "math/rand"
+)
+
+
+func Test_skewbinomial_insert_pop(t *testing.T) {
+    const SIZE = 1000
+    rand.Seed(1)
+
+    sh := NewSkewBinomialHeap()
+    insert_mult_pq(sh, shuffle(interval(0, SIZE)))
+    if err := sh.Validate(); err != nil {t.Fatal(err)}
+
+    for i:=0; i<SIZE; i++ {
+        if sh.Peek() != i {t.Fatalf("expected peek %d, got %d", i, sh.Peek())}
+        if pval := sh.Pop(); pval != i {t.Fatalf("expected %d, got %d", i, pval)}
+        if err := sh.Validate(); err != nil {t.Fatal(err)}
+    }
+}
+
+
+func Test_skewbinomial_forest(t *testing.T) {
+    // Inserting 2^k-1 keys leaves a single skew-binomial tree of rank k-1.
+    sh := NewSkewBinomialHeap()
+    insert_mult_pq(sh, interval(0, 15))
+
+    if listLength(sh.trees.children_head) != 1 || sh.trees.children_head.rank != 3 {
+        t.Errorf("expected a single tree of rank 3")
+    }
+}
+
+
+func Test_skewbinomial_merge_self(t *testing.T) {
+    sh := NewSkewBinomialHeap()
+    insert_mult_pq(sh, interval(0, 10))
+
+    mustPanic(t, func() { sh.Merge(sh) })
+    if sh.Size() != 10 {t.Errorf("expected size 10, got %d", sh.Size())}
+    if err := sh.Validate(); err != nil {t.Fatal(err)}
+    for i:=0; i<10; i++ {
+        if pval := sh.Pop(); pval != i {t.Fatalf("expected %d, got %d", i, pval)}
+    }
+}
+
+
+func Test_skewbinomial_random(t *testing.T) {
+    rng := rand.New(rand.NewSource(1))
+    sh := NewSkewBinomialHeap()
+    ref := NewBOHeap()
+
+    for i:=0; i<5000; i++ {
+        switch op := rng.Intn(10); {
+        case op < 5 || ref.Size() == 0:
+            value := rng.Intn(1000)
+            sh.Insert(value)
+            ref.Insert(value)
+        case op < 9:
+            if sval, rval := sh.Pop(), ref.Pop(); sval != rval {t.Fatalf("op %d: expected %d, got %d", i, rval, sval)}
+        default:
+            other := NewSkewBinomialHeap()
+            for j:=0; j<rng.Intn(20); j++ {
+                value := rng.Intn(1000)
+                other.Insert(value)
+                ref.Insert(value)
+            }
+            sh.Merge(other)
+            if other.Size() != 0 {t.Fatalf("merged heap not emptied")}
+        }
+
+        if sh.Size() != ref.Size() {t.Fatalf("op %d: expected size %d, got %d", i, ref.Size(), sh.Size())}
+        if err := sh.Validate(); err != nil {t.Fatalf("op %d: %v", i, err)}
+    }
+}
@@ -0,0 +1,33 @@
+package BrodalOkasakiHeap
+
+
+/*
+Sequence numbers are shared by all stable heaps, as they must stay distinct when heaps are merged.
+ */
+var stableSeq uint64
+
+
+/*
+Create a new Brodal-Okasaki heap that pops equal keys in the order they were inserted, popping its keys in the given
+order otherwise.
+
+Every inserted node is stamped with a sequence number, and "Order.before" breaks ties between equal keys with it. This
+costs 8 bytes per node, which every BONode carries anyway, and nothing for a heap that is not stable.
+
+The sequence numbers come from "stableSeq", so when stable heaps are merged, equal keys still pop in the order they were
+inserted, whichever heap they were inserted into. Nodes of a heap that is not stable carry 0, so merged into a stable
+heap they pop before the equal keys stamped there.
+ */
+func NewStableBOHeap(order Order) *BOHeap {
+    bq := NewOrderedBOHeap(order)
+    bq.stable = true
+    return bq
+}
+
+
+/*
+Report whether the heap pops equal keys in insertion order.
+ */
+func (bq *BOHeap) Stable() bool {
+    return bq.stable
+}
@@ -0,0 +1,96 @@
+package BrodalOkasakiHeap
+
+
+import (
+    "testing"
+    "math/rand"
+)
+
+
+/*
+Pop everything off a stable heap, checking that equal keys come out in the order they went in. Keys are ints, so the
+sequence number of the root tells which of the equal keys we got.
+ */
+func pop_stable(t *testing.T, bq *BOHeap) {
+    var lastvalue int
+    var lastseq uint64
+
+    for i:=0; bq.Size() > 0; i++ {
+        seq := bq.root.seq
+        value := bq.Pop()
+        if i > 0 && value == lastvalue && seq < lastseq {
+            t.Fatalf("key %d inserted as #%d popped after #%d", value, seq, lastseq)
+        }
+        lastvalue, lastseq = value, seq
+        if err := bq.Validate(); err != nil {t.Fatal(err)}
+    }
+}
+
+
+func Test_stable_equal_keys(t *testing.T) {
+    const SIZE = 1000
+    rng := rand.New(rand.NewSource(1))
+
+    for _, order := range []Order{MinFirst, MaxFirst} {
+        bq := NewStableBOHeap(order)
+        for i:=0; i<SIZE; i++ {
+            bq.Insert(rng.Intn(10))
+        }
+        pop_stable(t, bq)
+    }
+}
+
+
+func Test_stable_interleaved(t *testing.T) {
+    rng := rand.New(rand.NewSource(1))
+    bq := NewStableBOHeap(MinFirst)
+
+    var lastseq [5]uint64
+    for i:=0; i<5000; i++ {
+        if bq.Size() == 0 || rng.Intn(3) > 0 {
+            bq.Insert(rng.Intn(5))
+            continue
+        }
+
+        seq := bq.root.seq
+        value := bq.Pop()
+        if seq < lastseq[value] {t.Fatalf("op %d: key %d inserted as #%d popped after #%d", i, value, seq, lastseq[value])}
+        lastseq[value] = seq
+    }
+}
+
+
+func Test_stable_merge(t *testing.T) {
+    b1 := NewStableBOHeap(MinFirst)
+    b2 := NewStableBOHeap(MinFirst)
+
+    // Interleave the inserts, so that each heap's numbers alone would not tell the order.
+    for i:=0; i<10; i++ {
+        if i % 3 == 0 {
+            b1.Insert(1)
+        } else {
+            b2.Insert(1)
+        }
+    }
+    b1.Merge(b2)
+    insert_mult(b1, []int{1, 1})
+    b2.Insert(1)
+    b1.Merge(b2)
+
+    var seqs []uint64
+    for b1.Size() > 0 {
+        seqs = append(seqs, b1.root.seq)
+        b1.Pop()
+    }
+    if len(seqs) != 13 {t.Fatalf("expected 13 keys, got %d", len(seqs))}
+    for i:=1; i<len(seqs); i++ {
+        if seqs[i] <= seqs[i-1] {t.Fatalf("equal keys not in insertion order: %v", seqs)}
+    }
+}
+
+
+func Test_unstable_no_seq(t *testing.T) {
+    bq := NewBOHeap()
+    insert_mult(bq, []int{3, 3, 3})
+    if bq.Stable() || bq.root.seq != 0 {t.Errorf("plain heap stamps sequence numbers")}
+}
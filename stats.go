@@ -0,0 +1,100 @@
+package BrodalOkasakiHeap
+
+
+/*
+Counters describing how much restructuring a heap actually did. Big-O tells us Pop() is O(logn), these numbers tell us
+how large that "logn" turns out to be for a given workload.
+
+Counting is disabled by default. A heap without stats only pays a nil check at every counting point.
+ */
+type Stats struct {
+    SkewLinks			int		// Skew-links that joined three nodes into one tree.
+    SimpleLinks			int		// Binomial links performed while inserting a node of rank >0.
+    BinomialCascades	int		// Binomial links whose input was itself the result of the previous link.
+    RootSwaps			int		// Times an inserted node was smaller than the root and replaced it.
+    SubqueueMerges		int		// Subqueues dissolved into the children of the root.
+
+    Pops				int		// Number of Pop() calls.
+    NodesTouched		int		// Nodes re-inserted by all Pop() calls, including the removed node itself.
+    MaxNodesTouched		int		// The largest amount of nodes re-inserted by a single Pop().
+}
+
+
+/*
+Start counting. Counters of a heap that is already counting are left untouched.
+ */
+func (bq *BOHeap) EnableStats() {
+    if bq.stats == nil {
+        bq.stats = &Stats{}
+    }
+}
+
+
+/*
+Stop counting and throw the collected counters away.
+ */
+func (bq *BOHeap) DisableStats() {
+    bq.stats = nil
+}
+
+
+/*
+Return a copy of the counters. A heap that is not counting reports all zeros.
+ */
+func (bq *BOHeap) Stats() Stats {
+    if bq.stats == nil {
+        return Stats{}
+    }
+    return *bq.stats
+}
+
+
+/*
+Zero the counters without disabling them.
+ */
+func (bq *BOHeap) ResetStats() {
+    if bq.stats != nil {
+        *bq.stats = Stats{}
+    }
+}
+
+
+/*
+Account a finished Pop() that had to re-insert "touched" nodes.
+ */
+func (st *Stats) countPop(touched int) {
+    st.Pops += 1
+    st.NodesTouched += touched
+    if touched > st.MaxNodesTouched {
+        st.MaxNodesTouched = touched
+    }
+}
+
+
+// Same as the ones of BOHeap, for CompactBOHeap.
+
+func (ch *CompactBOHeap) EnableStats() {
+    if ch.stats == nil {
+        ch.stats = &Stats{}
+    }
+}
+
+
+func (ch *CompactBOHeap) DisableStats() {
+    ch.stats = nil
+}
+
+
+func (ch *CompactBOHeap) Stats() Stats {
+    if ch.stats == nil {
+        return Stats{}
+    }
+    return *ch.stats
+}
+
+
+func (ch *CompactBOHeap) ResetStats() {
+    if ch.stats != nil {
+        *ch.stats = Stats{}
+    }
+}
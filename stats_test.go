@@ -0,0 +1,87 @@
+package BrodalOkasakiHeap
+
+
+import (
+    "testing"
+    "math/rand"
+)
+
+
+func Test_stats_disabled(t *testing.T) {
+    heap := NewBOHeap()
+    insert_mult(heap, interval(0, 30))
+    heap.Pop()
+
+    if heap.Stats() != (Stats{}) {t.Errorf("counted without EnableStats()")}
+}
+
+
+func Test_stats_insert(t *testing.T) {
+    heap := NewBOHeap()
+    heap.EnableStats()
+
+    // 1 becomes the root, 2 and 3 are plain children, 4 skew-links them.
+    insert_mult(heap, []int{1, 2, 3, 4})
+
+    st := heap.Stats()
+    if st.SkewLinks != 1 {t.Errorf("expected 1 skew link, got %d", st.SkewLinks)}
+    if st.RootSwaps != 0 {t.Errorf("expected no root swaps, got %d", st.RootSwaps)}
+
+    heap.Insert(0)
+    st = heap.Stats()
+    if st.RootSwaps != 1 {t.Errorf("expected 1 root swap, got %d", st.RootSwaps)}
+}
+
+
+func Test_stats_pop(t *testing.T) {
+    const SIZE = 100
+    rand.Seed(1)
+
+    heap := NewBOHeap()
+    heap.EnableStats()
+    insert_mult(heap, shuffle(interval(0, SIZE)))
+
+    for i:=0; i<SIZE; i++ {
+        heap.Pop()
+    }
+
+    st := heap.Stats()
+    if st.Pops != SIZE {t.Errorf("expected %d pops, got %d", SIZE, st.Pops)}
+    if st.NodesTouched < SIZE-1 {t.Errorf("expected at least %d touched nodes, got %d", SIZE-1, st.NodesTouched)}
+    if st.MaxNodesTouched == 0 || st.MaxNodesTouched > st.NodesTouched {t.Errorf("bad max touched %d", st.MaxNodesTouched)}
+    if st.SimpleLinks == 0 {t.Errorf("expected binomial links")}
+    if st.BinomialCascades > st.SimpleLinks {t.Errorf("more cascades than links")}
+}
+
+
+func Test_stats_merge(t *testing.T) {
+    h1 := NewBOHeap()
+    h2 := NewBOHeap()
+    insert_mult(h1, interval(10, 20))
+    insert_mult(h2, interval(0, 10))
+
+    // The root of h2 is smaller, so it becomes the root of h1 and its subqueue is dissolved by the first Pop().
+    h1.EnableStats()
+    h1.Merge(h2)
+    h1.Pop()
+
+    if h1.Stats().SubqueueMerges != 1 {t.Errorf("expected 1 subqueue merge, got %d", h1.Stats().SubqueueMerges)}
+}
+
+
+func Test_stats_reset(t *testing.T) {
+    heap := NewBOHeap()
+    heap.EnableStats()
+    insert_mult(heap, interval(0, 10))
+    heap.Pop()
+
+    heap.ResetStats()
+    if heap.Stats() != (Stats{}) {t.Errorf("counters not reset")}
+
+    heap.Pop()
+    if heap.Stats().Pops != 1 {t.Errorf("counting stopped after reset")}
+
+    heap.DisableStats()
+    heap.Pop()
+    if heap.Stats() != (Stats{}) {t.Errorf("counted after DisableStats()")}
+}
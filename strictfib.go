@@ -0,0 +1,1046 @@
+package BrodalOkasakiHeap
+
+import (
+    "fmt"
+    "math"
+    "math/bits"
+    "sync/atomic"
+)
+
+
+/*
+A priority queue with worst case bounds all the way: Insert(), Peek(), Merge() and DecreaseKey() take O(1) time and
+Pop() and Delete() take O(logn) time, none of them amortized.
+
+Brodal's 1996 queue was the first to get there, at the price of a famously intricate structure. This is the strict
+Fibonacci heap of Brodal, Lagogiannis and Tarjan ("Strict Fibonacci Heaps", STOC 2012), which reaches the same bounds
+with a single heap ordered tree and a handful of O(1) transformations. The 1996 structure itself is not implemented.
+
+In short:
+    * Every node is active or passive, and the root is passive. An active node is an active root when its parent is
+      passive. The rank of an active node is the amount of its active children, and an active node that is not an
+      active root collects a loss every time it loses an active child.
+    * Every operation does a constant amount of transformations that keep the amount of active roots, the total loss
+      and the degree of the root within O(logn): "reduceActiveRoots", "reduceRootDegree" and "reduceLoss".
+    * Merge() turns every node of the smaller heap passive at once, by switching off the flag they share.
+    * Every node but the root waits in a queue. Pop() moves two passive children of the first two nodes of the queue up
+      to the root each time, which keeps the degree of every node within O(logn).
+
+The constants are large, so in practice this loses to PairingHeap and FibonacciHeap on nearly every workload. It is
+here for the cases where a single slow operation is not acceptable.
+ */
+type StrictFibonacciHeap struct {
+    root		*sfNode
+    size		int
+
+    // Children of the root come in three parts: active roots, passive nodes with active children ("non-linkable")
+    // and passive nodes without ("linkable"). These point to the first node of the last two parts, nil when empty.
+    nonlinkable	*sfNode
+    linkable	*sfNode
+    degree		int				// Degree of the root.
+
+    queue		*sfNode			// Head of the queue of all nodes but the root.
+
+    fix			sfFix
+}
+
+
+/*
+Bookkeeping of the active nodes, which makes finding the next transformation O(1). Merge() keeps the one of the larger
+heap and drops the other.
+ */
+type sfFix struct {
+    active		*sfActive		// Flag shared by every active node.
+    rank0		*sfRank			// The records of all ranks form a list, starting at rank 0.
+
+    rootRanks	*sfRank			// Ranks with two or more active roots.
+    lossRanks	*sfRank			// Ranks with two or more nodes of loss 1.
+    lossy		*sfNode			// Nodes with a loss of 2 or more.
+
+    activeRoots	int
+    totalLoss	int
+}
+
+
+type sfActive struct {
+    on	bool
+}
+
+
+/*
+Every list of nodes is circular and doubly linked: siblings, the queue, and the fix lists of "sfFix" and "sfRank".
+ */
+const (
+    sfSiblings = iota
+    sfQueue
+    sfFixList
+)
+
+
+type sfLinks struct {
+    prev	*sfNode
+    next	*sfNode
+}
+
+
+/*
+Where an active node is kept in the fix lists.
+ */
+const (
+    sfNone = iota
+    sfActiveRoot
+    sfLoss1
+    sfLossy
+)
+
+
+type sfNode struct {
+    item		*sfItem
+    parent		*sfNode
+    child		*sfNode			// The leftmost child. Active children come before passive ones.
+    links		[3]sfLinks
+
+    // Only meaningful while the node is active.
+    flag		*sfActive
+    rank		*sfRank
+    loss		int
+    state		int
+}
+
+
+/*
+The key lives apart from its node, because DecreaseKey() may swap the keys of two nodes. This is the handle.
+ */
+type sfItem struct {
+    key		int
+    seq		uint64			// Breaks ties between equal keys, so that no two keys are ever equal.
+    node	*sfNode
+    gone	bool
+}
+
+
+func (it *sfItem) Value() int {
+    return it.key
+}
+
+
+type sfRank struct {
+    rank		int
+    inc			*sfRank
+    dec			*sfRank
+
+    roots		*sfNode			// Active roots of this rank.
+    nroots		int
+    loss1		*sfNode			// Active nodes of this rank with loss 1.
+    nloss1		int
+
+    // Links for "sfFix.rootRanks" and "sfFix.lossRanks".
+    rankLinks	[2]struct{ prev, next *sfRank }
+    listed		[2]bool
+}
+
+
+/*
+Sequence numbers are shared by all heaps, as they must stay distinct when heaps are merged.
+ */
+var sfSeq uint64
+
+
+/*
+Create a new strict Fibonacci heap.
+ */
+func NewStrictFibonacciHeap() *StrictFibonacciHeap {
+    return &StrictFibonacciHeap{}
+}
+
+
+func (sh *StrictFibonacciHeap) Insert(value int) {
+    sh.Push(value)
+}
+
+
+func (sh *StrictFibonacciHeap) Push(value int) Handle {
+    node := &sfNode{}
+    node.item = &sfItem{key: value, seq: atomic.AddUint64(&sfSeq, 1), node: node}
+    sh.size += 1
+
+    if sh.root == nil {
+        sh.root = node
+        return node.item
+    }
+
+    // Merge() with a heap of a single node.
+    if sfLess(node, sh.root) {
+        oldroot := sh.root
+        sh.root = node
+        sh.nonlinkable = nil
+        sh.linkable = nil
+        sh.degree = 0
+        sh.addRootChild(oldroot)
+        sfPushBack(&sh.queue, oldroot, sfQueue)
+    } else {
+        sh.addRootChild(node)
+        sfPushBack(&sh.queue, node, sfQueue)
+    }
+
+    sh.reduceActiveRoots()
+    sh.reduceRootDegree()
+    return node.item
+}
+
+
+func (sh *StrictFibonacciHeap) Peek() int {
+    if sh.root == nil {
+        panic("StrictFibonacciHeap: Peek() on an empty heap")
+    }
+    return sh.root.item.key
+}
+
+
+func (sh *StrictFibonacciHeap) PeekHandle() Handle {
+    if sh.root == nil {
+        panic("StrictFibonacciHeap: PeekHandle() on an empty heap")
+    }
+    return sh.root.item
+}
+
+
+func (sh *StrictFibonacciHeap) Size() int {
+    return sh.size
+}
+
+
+/*
+"other" must be another *StrictFibonacciHeap. Its nodes now belong to this heap, so it is left empty.
+ */
+func (sh *StrictFibonacciHeap) Merge(pq PriorityQueue) {
+    other, ok := pq.(*StrictFibonacciHeap)
+    if !ok {
+        panic("StrictFibonacciHeap: can only merge with another *StrictFibonacciHeap")
+    }
+    if other == sh {
+        panic("StrictFibonacciHeap: can't merge a heap with itself")
+    }
+    if other.root == nil {
+        return
+    }
+    if sh.root == nil {
+        *sh, *other = *other, StrictFibonacciHeap{}
+        return
+    }
+
+    small, big := sh, other
+    if small.size > big.size {
+        small, big = big, small
+    }
+    if small.fix.active != nil {
+        small.fix.active.on = false
+    }
+
+    // The root with the larger key becomes a child of the other one.
+    top, bottom := small, big
+    if sfLess(big.root, small.root) {
+        top, bottom = big, small
+    }
+    merged := StrictFibonacciHeap {
+        root: top.root,
+        size: small.size + big.size,
+        nonlinkable: top.nonlinkable,
+        linkable: top.linkable,
+        degree: top.degree,
+        queue: big.queue,
+        fix: big.fix,
+    }
+    if top == small {
+        // Every child of our root just went passive, along with everything below.
+        merged.nonlinkable = nil
+        merged.linkable = top.root.child
+    }
+    sfPushBack(&merged.queue, bottom.root, sfQueue)
+    sfConcat(&merged.queue, small.queue, sfQueue)
+    merged.addRootChild(bottom.root)
+
+    *sh, *other = merged, StrictFibonacciHeap{}
+
+    sh.reduceActiveRoots()
+    sh.reduceRootDegree()
+}
+
+
+func (sh *StrictFibonacciHeap) DecreaseKey(h Handle, value int) {
+    item := sh.handleItem(h)
+    if value > item.key {
+        panic("StrictFibonacciHeap: DecreaseKey() to a larger key")
+    }
+    item.key = value
+    sh.decreased(item.node)
+}
+
+
+/*
+Move the key of the handle above every other key, then pop it.
+ */
+func (sh *StrictFibonacciHeap) Delete(h Handle) {
+    item := sh.handleItem(h)
+    item.key = math.MinInt
+    item.seq = 0  // Taken by no other key.
+    sh.decreased(item.node)
+    sh.Pop()
+}
+
+
+func (sh *StrictFibonacciHeap) handleItem(h Handle) *sfItem {
+    item, ok := h.(*sfItem)
+    if !ok || item.gone {
+        panic("StrictFibonacciHeap: handle does not belong to a key of a strict Fibonacci heap")
+    }
+    return item
+}
+
+
+/*
+The key of "node" just went down. The node is cut off its parent and linked to the root, swapping keys with the root
+first if it is smaller now.
+ */
+func (sh *StrictFibonacciHeap) decreased(node *sfNode) {
+    if node == sh.root {
+        return
+    }
+    if sfLess(node, sh.root) {
+        root := sh.root
+        node.item, root.item = root.item, node.item
+        node.item.node = node
+        root.item.node = root
+    }
+
+    parent := node.parent
+    wasactive := node.isActive()
+    sh.fixRemove(node)
+    sh.fixRemove(parent)
+
+    sh.cut(node)
+    node.loss = 0
+    sh.addRootChild(node)
+    if wasactive && parent.isActive() {
+        parent.rank = parent.rank.dec
+        if parent.parent.isActive() {
+            parent.loss += 1
+        }
+    }
+
+    sh.fixAdd(node)
+    sh.fixAdd(parent)
+
+    sh.reduceLoss()
+    for i:=0; i<6 && sh.reduceActiveRoots(); i++ {}
+    for i:=0; i<4 && sh.reduceRootDegree(); i++ {}
+}
+
+
+/*
+The child of the root with the smallest key becomes the new root, taking over the other children of the old root.
+ */
+func (sh *StrictFibonacciHeap) Pop() int {
+    if sh.root == nil {
+        panic("StrictFibonacciHeap: Pop() on an empty heap")
+    }
+
+    oldroot := sh.root
+    oldroot.item.gone = true
+    sh.size -= 1
+    if sh.size == 0 {
+        sh.root = nil
+        sh.nonlinkable = nil
+        sh.linkable = nil
+        sh.degree = 0
+        return oldroot.item.key
+    }
+
+    newroot := oldroot.child
+    for child := newroot.links[sfSiblings].next; child != oldroot.child; child = child.links[sfSiblings].next {
+        if sfLess(child, newroot) {
+            newroot = child
+        }
+    }
+    sfRemove(&oldroot.child, newroot, sfSiblings)
+    sfRemove(&sh.queue, newroot, sfQueue)
+
+    // The root is passive, so the active children of the new root turn into active roots.
+    sh.fixRemove(newroot)
+    wasactive := newroot.isActive()
+    newroot.flag = nil
+    if wasactive && newroot.child != nil {
+        for child := newroot.child; child.isActive(); {
+            sh.fixRemove(child)
+            child.loss = 0
+            sh.fixAdd(child)
+
+            child = child.links[sfSiblings].next
+            if child == newroot.child {
+                break
+            }
+        }
+    }
+
+    // Sort the children of both into the three parts the children of a root come in.
+    var active, nonlinkable, linkable *sfNode
+    degree := 0
+    for _, head := range []**sfNode{&newroot.child, &oldroot.child} {
+        for *head != nil {
+            child := *head
+            sfRemove(head, child, sfSiblings)
+            child.parent = newroot
+            degree += 1
+
+            if child.isActive() {
+                sfPushBack(&active, child, sfSiblings)
+            } else if child.isLinkable() {
+                sfPushBack(&linkable, child, sfSiblings)
+            } else {
+                sfPushBack(&nonlinkable, child, sfSiblings)
+            }
+        }
+    }
+    sfConcat(&active, nonlinkable, sfSiblings)
+    sfConcat(&active, linkable, sfSiblings)
+
+    newroot.parent = nil
+    newroot.child = active
+    sh.root = newroot
+    sh.nonlinkable = nonlinkable
+    sh.linkable = linkable
+    sh.degree = degree
+
+    // Twice, the first node of the queue goes to the back, giving up to two of its rightmost passive children to the
+    // root. This is what keeps the degrees of the nodes in check.
+    for i:=0; i<2 && sh.queue != nil; i++ {
+        node := sh.queue
+        sh.queue = node.links[sfQueue].next
+
+        for j:=0; j<2 && node.child != nil; j++ {
+            child := node.child.links[sfSiblings].prev
+            if child.isActive() {
+                break
+            }
+            sh.cut(child)
+            sh.addRootChild(child)
+        }
+    }
+
+    for sh.reduceLoss() || sh.reduceActiveRoots() || sh.reduceRootDegree() {}
+
+    return oldroot.item.key
+}
+
+
+// ====== Transformations ======
+
+
+/*
+Link two active roots of the same rank, the larger one becoming an active child of the other. If that leaves a passive
+node as the rightmost child of the winner, it moves up to the root. One active root less, the root gets at most one
+more child.
+ */
+func (sh *StrictFibonacciHeap) reduceActiveRoots() bool {
+    rank := sh.fix.rootRanks
+    if rank == nil {
+        return false
+    }
+
+    winner := rank.roots
+    loser := winner.links[sfFixList].next
+    if sfLess(loser, winner) {
+        winner, loser = loser, winner
+    }
+
+    sh.fixRemove(winner)
+    sh.fixRemove(loser)
+    sh.cut(loser)
+    sh.addChild(winner, loser)
+    winner.rank = winner.rank.increment()
+    sh.fixAdd(loser)
+    sh.fixAdd(winner)
+
+    rightmost := winner.child.links[sfSiblings].prev
+    if !rightmost.isActive() {
+        sh.cut(rightmost)
+        sh.addRootChild(rightmost)
+    }
+    return true
+}
+
+
+/*
+Take the three rightmost children of the root, if they are all linkable, and turn them into a single active root of
+rank 1: the smallest of them gets the middle one as an active child, which gets the largest one as a passive child.
+The root loses two children, at the price of one more active root.
+ */
+func (sh *StrictFibonacciHeap) reduceRootDegree() bool {
+    if sh.degree < 3 {
+        return false
+    }
+
+    z := sh.root.child.links[sfSiblings].prev
+    y := z.links[sfSiblings].prev
+    x := y.links[sfSiblings].prev
+    if !x.isLinkable() || !y.isLinkable() || !z.isLinkable() {
+        return false
+    }
+
+    if sfLess(y, x) { x, y = y, x }
+    if sfLess(z, y) { y, z = z, y }
+    if sfLess(y, x) { x, y = y, x }
+
+    sh.cut(x)
+    sh.cut(y)
+    sh.cut(z)
+    sh.activate(x)
+    sh.activate(y)
+
+    sh.addChild(y, z)
+    sh.addChild(x, y)
+    x.rank = x.rank.increment()
+    sh.addRootChild(x)
+
+    sh.fixAdd(y)
+    sh.fixAdd(x)
+    return true
+}
+
+
+/*
+Bring the total loss down by at least one, in one of two ways:
+    * A node of loss 2 or more becomes an active root, passing a loss of 1 on to its parent.
+    * Of two nodes of loss 1 and the same rank, the larger one becomes an active child of the other, and both lose
+      their loss. The parent of the larger one gets a loss of 1.
+ */
+func (sh *StrictFibonacciHeap) reduceLoss() bool {
+    if node := sh.fix.lossy; node != nil {
+        parent := node.parent
+        sh.fixRemove(node)
+        sh.fixRemove(parent)
+
+        sh.cut(node)
+        node.loss = 0
+        sh.addRootChild(node)
+        parent.rank = parent.rank.dec
+        if parent.parent.isActive() {
+            parent.loss += 1
+        }
+
+        sh.fixAdd(node)
+        sh.fixAdd(parent)
+        return true
+    }
+
+    if rank := sh.fix.lossRanks; rank != nil {
+        winner := rank.loss1
+        loser := winner.links[sfFixList].next
+        if sfLess(loser, winner) {
+            winner, loser = loser, winner
+        }
+        parent := loser.parent
+
+        sh.fixRemove(winner)
+        sh.fixRemove(loser)
+        sh.fixRemove(parent)  // Does nothing when it is the winner, which was just removed.
+
+        sh.cut(loser)
+        parent.rank = parent.rank.dec
+        if parent.parent.isActive() {
+            parent.loss += 1
+        }
+        sh.addChild(winner, loser)
+        winner.rank = winner.rank.increment()
+        winner.loss = 0
+        loser.loss = 0
+
+        sh.fixAdd(loser)
+        sh.fixAdd(winner)
+        if parent != winner {
+            sh.fixAdd(parent)
+        }
+        return true
+    }
+
+    return false
+}
+
+
+// ====== Nodes ======
+
+
+/*
+Compare by key, then by sequence number.
+ */
+func sfLess(a *sfNode, b *sfNode) bool {
+    if a.item.key != b.item.key {
+        return a.item.key < b.item.key
+    }
+    return a.item.seq < b.item.seq
+}
+
+
+func (node *sfNode) isActive() bool {
+    return node.flag != nil && node.flag.on
+}
+
+
+/*
+A passive node with no active children. Active children come first, so looking at the leftmost one is enough.
+ */
+func (node *sfNode) isLinkable() bool {
+    return !node.isActive() && (node.child == nil || !node.child.isActive())
+}
+
+
+func (sh *StrictFibonacciHeap) activate(node *sfNode) {
+    if sh.fix.active == nil {
+        sh.fix.active = &sfActive{on: true}
+        sh.fix.rank0 = &sfRank{}
+    }
+    node.flag = sh.fix.active
+    node.rank = sh.fix.rank0
+    node.loss = 0
+    node.state = sfNone
+}
+
+
+/*
+Make "child" a child of a node other than the root: on the left when it is active, on the right otherwise.
+ */
+func (sh *StrictFibonacciHeap) addChild(parent *sfNode, child *sfNode) {
+    child.parent = parent
+    if child.isActive() {
+        sfPushFront(&parent.child, child, sfSiblings)
+    } else {
+        sfPushBack(&parent.child, child, sfSiblings)
+    }
+}
+
+
+/*
+Make "child" a child of the root, in its part of the children.
+ */
+func (sh *StrictFibonacciHeap) addRootChild(child *sfNode) {
+    root := sh.root
+    child.parent = root
+    sh.degree += 1
+
+    switch {
+    case child.isActive():
+        sfPushFront(&root.child, child, sfSiblings)
+    case child.isLinkable():
+        sfPushBack(&root.child, child, sfSiblings)
+        if sh.linkable == nil {
+            sh.linkable = child
+        }
+    default:
+        if sh.linkable == nil {
+            sfPushBack(&root.child, child, sfSiblings)
+        } else {
+            sfSplice(sh.linkable, child, sfSiblings)
+            if sh.linkable == root.child {
+                root.child = child
+            }
+        }
+        if sh.nonlinkable == nil {
+            sh.nonlinkable = child
+        }
+    }
+}
+
+
+/*
+Cut a node off its parent. A passive child of the root that loses its last active child becomes linkable, so it moves
+to that part of the children.
+ */
+func (sh *StrictFibonacciHeap) cut(node *sfNode) {
+    parent := node.parent
+    if parent == sh.root {
+        sh.removeRootChild(node)
+        return
+    }
+
+    sfRemove(&parent.child, node, sfSiblings)
+    node.parent = nil
+
+    // Having had an active child, the parent was among the non-linkable ones.
+    if node.isActive() && parent.parent == sh.root && parent.isLinkable() {
+        sh.removeRootChild(parent)
+        sh.addRootChild(parent)
+    }
+}
+
+
+func (sh *StrictFibonacciHeap) removeRootChild(child *sfNode) {
+    root := sh.root
+    next := child.links[sfSiblings].next
+
+    if child == sh.nonlinkable {
+        sh.nonlinkable = nil
+        if next != sh.linkable && next != root.child {
+            sh.nonlinkable = next
+        }
+    }
+    if child == sh.linkable {
+        sh.linkable = nil
+        if next != root.child {
+            sh.linkable = next
+        }
+    }
+    sfRemove(&root.child, child, sfSiblings)
+    child.parent = nil
+    sh.degree -= 1
+}
+
+
+// ====== Fix lists ======
+
+
+/*
+File an active node under its rank or its loss, whichever applies. Does nothing for a passive node.
+ */
+func (sh *StrictFibonacciHeap) fixAdd(node *sfNode) {
+    if !node.isActive() {
+        return
+    }
+    rank := node.rank
+
+    switch {
+    case !node.parent.isActive():
+        node.state = sfActiveRoot
+        sfPushBack(&rank.roots, node, sfFixList)
+        rank.nroots += 1
+        if rank.nroots == 2 {
+            sfRankPush(&sh.fix.rootRanks, rank, 0)
+        }
+        sh.fix.activeRoots += 1
+    case node.loss == 1:
+        node.state = sfLoss1
+        sfPushBack(&rank.loss1, node, sfFixList)
+        rank.nloss1 += 1
+        if rank.nloss1 == 2 {
+            sfRankPush(&sh.fix.lossRanks, rank, 1)
+        }
+        sh.fix.totalLoss += 1
+    case node.loss > 1:
+        node.state = sfLossy
+        sfPushBack(&sh.fix.lossy, node, sfFixList)
+        sh.fix.totalLoss += node.loss
+    default:
+        node.state = sfNone
+    }
+}
+
+
+/*
+Undo "fixAdd", before changing the rank, the loss or the parent of a node. Does nothing for a passive node.
+ */
+func (sh *StrictFibonacciHeap) fixRemove(node *sfNode) {
+    if !node.isActive() {
+        return
+    }
+    rank := node.rank
+
+    switch node.state {
+    case sfActiveRoot:
+        sfRemove(&rank.roots, node, sfFixList)
+        rank.nroots -= 1
+        if rank.nroots == 1 {
+            sfRankRemove(&sh.fix.rootRanks, rank, 0)
+        }
+        sh.fix.activeRoots -= 1
+    case sfLoss1:
+        sfRemove(&rank.loss1, node, sfFixList)
+        rank.nloss1 -= 1
+        if rank.nloss1 == 1 {
+            sfRankRemove(&sh.fix.lossRanks, rank, 1)
+        }
+        sh.fix.totalLoss -= 1
+    case sfLossy:
+        sfRemove(&sh.fix.lossy, node, sfFixList)
+        sh.fix.totalLoss -= node.loss
+    }
+    node.state = sfNone
+}
+
+
+func (rank *sfRank) increment() *sfRank {
+    if rank.inc == nil {
+        rank.inc = &sfRank{rank: rank.rank + 1, dec: rank}
+    }
+    return rank.inc
+}
+
+
+// ====== Lists ======
+
+
+/*
+Put "node" right before "at" in list "k".
+ */
+func sfSplice(at *sfNode, node *sfNode, k int) {
+    prev := at.links[k].prev
+    node.links[k] = sfLinks{prev, at}
+    prev.links[k].next = node
+    at.links[k].prev = node
+}
+
+
+func sfPushBack(head **sfNode, node *sfNode, k int) {
+    if *head == nil {
+        node.links[k] = sfLinks{node, node}
+        *head = node
+    } else {
+        sfSplice(*head, node, k)
+    }
+}
+
+
+func sfPushFront(head **sfNode, node *sfNode, k int) {
+    sfPushBack(head, node, k)
+    *head = node
+}
+
+
+func sfRemove(head **sfNode, node *sfNode, k int) {
+    next := node.links[k].next
+    if next == node {
+        *head = nil
+    } else {
+        if *head == node {
+            *head = next
+        }
+        prev := node.links[k].prev
+        prev.links[k].next = next
+        next.links[k].prev = prev
+    }
+    node.links[k] = sfLinks{}
+}
+
+
+/*
+Append the list starting at "other" to the list of "head".
+ */
+func sfConcat(head **sfNode, other *sfNode, k int) {
+    if other == nil {
+        return
+    }
+    if *head == nil {
+        *head = other
+        return
+    }
+
+    tail := (*head).links[k].prev
+    othertail := other.links[k].prev
+    tail.links[k].next = other
+    other.links[k].prev = tail
+    othertail.links[k].next = *head
+    (*head).links[k].prev = othertail
+}
+
+
+func sfRankPush(head **sfRank, rank *sfRank, k int) {
+    rank.listed[k] = true
+    if *head == nil {
+        rank.rankLinks[k].prev = rank
+        rank.rankLinks[k].next = rank
+        *head = rank
+        return
+    }
+
+    prev := (*head).rankLinks[k].prev
+    rank.rankLinks[k].prev = prev
+    rank.rankLinks[k].next = *head
+    prev.rankLinks[k].next = rank
+    (*head).rankLinks[k].prev = rank
+}
+
+
+func sfRankRemove(head **sfRank, rank *sfRank, k int) {
+    rank.listed[k] = false
+    next := rank.rankLinks[k].next
+    if next == rank {
+        *head = nil
+    } else {
+        if *head == rank {
+            *head = next
+        }
+        prev := rank.rankLinks[k].prev
+        prev.rankLinks[k].next = next
+        next.rankLinks[k].prev = prev
+    }
+    rank.rankLinks[k].prev = nil
+    rank.rankLinks[k].next = nil
+}
+
+
+// ====== Validation ======
+
+
+/*
+Check the structure of the heap and the invariants its bounds rest on, as stated in the paper with R = 2logn + 6:
+    * The total loss and the amount of active roots are at most R+1.
+    * The root has at most R+3 children.
+
+Along with these, the heap order, the links, the parts of the children of the root, the ranks, the fix lists and the
+queue are checked. Takes O(n) time, it is meant for tests.
+ */
+func (sh *StrictFibonacciHeap) Validate() error {
+    if sh.root == nil {
+        if sh.size != 0 {
+            return fmt.Errorf("empty heap with size %d", sh.size)
+        }
+        return nil
+    }
+    if sh.root.parent != nil || sh.root.isActive() {
+        return fmt.Errorf("root %d is not a passive root", sh.root.item.key)
+    }
+
+    v := &sfValidation{}
+    if err := sh.validateNode(sh.root, v); err != nil {
+        return err
+    }
+    if v.count != sh.size {
+        return fmt.Errorf("heap holds %d nodes but has size %d", v.count, sh.size)
+    }
+    if v.activeRoots != sh.fix.activeRoots || v.totalLoss != sh.fix.totalLoss {
+        return fmt.Errorf("counted %d active roots and a total loss of %d, recorded %d and %d",
+            v.activeRoots, v.totalLoss, sh.fix.activeRoots, sh.fix.totalLoss)
+    }
+
+    queued := 0
+    if sh.queue != nil {
+        node := sh.queue
+        for {
+            queued += 1
+            if node.parent == nil || queued > sh.size {
+                return fmt.Errorf("queue holds the root or loops")
+            }
+            node = node.links[sfQueue].next
+            if node == sh.queue {
+                break
+            }
+        }
+    }
+    if queued != sh.size - 1 {
+        return fmt.Errorf("queue holds %d nodes, expected %d", queued, sh.size - 1)
+    }
+
+    R := 2*(bits.Len(uint(sh.size)) - 1) + 6
+    if sh.fix.totalLoss > R+1 {
+        return fmt.Errorf("total loss %d above %d", sh.fix.totalLoss, R+1)
+    }
+    if sh.fix.activeRoots > R+1 {
+        return fmt.Errorf("%d active roots, above %d", sh.fix.activeRoots, R+1)
+    }
+    if sh.degree > R+3 {
+        return fmt.Errorf("root degree %d above %d", sh.degree, R+3)
+    }
+    return nil
+}
+
+
+type sfValidation struct {
+    count		int
+    activeRoots	int
+    totalLoss	int
+}
+
+
+func (sh *StrictFibonacciHeap) validateNode(node *sfNode, v *sfValidation) error {
+    v.count += 1
+    if v.count > sh.size {
+        return fmt.Errorf("more than %d nodes reachable", sh.size)
+    }
+    if node.item.node != node || node.item.gone {
+        return fmt.Errorf("node %d holds a stale item", node.item.key)
+    }
+
+    if node.isActive() {
+        if node.flag != sh.fix.active {
+            return fmt.Errorf("active node %d from another heap", node.item.key)
+        }
+        expected := sfNone
+        switch {
+        case !node.parent.isActive():
+            expected = sfActiveRoot
+            v.activeRoots += 1
+            if node.loss != 0 {
+                return fmt.Errorf("active root %d has loss %d", node.item.key, node.loss)
+            }
+        case node.loss == 1:
+            expected = sfLoss1
+        case node.loss > 1:
+            expected = sfLossy
+        }
+        v.totalLoss += node.loss
+        if node.state != expected {
+            return fmt.Errorf("node %d is filed wrong", node.item.key)
+        }
+    }
+
+    if node.child == nil {
+        if node.isActive() && node.rank.rank != 0 {
+            return fmt.Errorf("node %d has rank %d but no children", node.item.key, node.rank.rank)
+        }
+        return nil
+    }
+
+    // 0: active, 1: passive non-linkable, 2: passive linkable. Only the children of the root tell the last two apart.
+    part := 0
+    var firsts [3]*sfNode
+    activechildren := 0
+    degree := 0
+    child := node.child
+    for {
+        degree += 1
+        if child.parent != node {
+            return fmt.Errorf("node %d does not point back to its parent %d", child.item.key, node.item.key)
+        }
+        if child.links[sfSiblings].next.links[sfSiblings].prev != child {
+            return fmt.Errorf("node %d has broken sibling links", child.item.key)
+        }
+        if sfLess(child, node) {
+            return fmt.Errorf("node %d comes before its parent %d", child.item.key, node.item.key)
+        }
+
+        childpart := 0
+        if child.isActive() {
+            activechildren += 1
+        } else if node == sh.root && child.isLinkable() {
+            childpart = 2
+        } else {
+            childpart = 1
+        }
+        if childpart < part {
+            return fmt.Errorf("children of %d are out of order", node.item.key)
+        }
+        if firsts[childpart] == nil {
+            firsts[childpart] = child
+        }
+        part = childpart
+
+        if err := sh.validateNode(child, v); err != nil {
+            return err
+        }
+        child = child.links[sfSiblings].next
+        if child == node.child {
+            break
+        }
+    }
+
+    if node.isActive() && node.rank.rank != activechildren {
+        return fmt.Errorf("node %d has rank %d but %d active children", node.item.key, node.rank.rank, activechildren)
+    }
+    if node == sh.root {
+        if degree != sh.degree {
+            return fmt.Errorf("root has %d children, recorded %d", degree, sh.degree)
+        }
+        if firsts[1] != sh.nonlinkable || firsts[2] != sh.linkable {
+            return fmt.Errorf("parts of the children of the root are not where they should be")
+        }
+    }
+    return nil
+}
@@ -0,0 +1,118 @@
+package BrodalOkasakiHeap
+
+
+import (
+    "testing"
+    "math/rand"
+)
+
+
+func Test_strictfib_insert_pop(t *testing.T) {
+    const SIZE = 2000
+    rand.Seed(1)
+
+    sh := NewStrictFibonacciHeap()
+    for _, value := range shuffle(interval(0, SIZE)) {
+        sh.Insert(value)
+        if err := sh.Validate(); err != nil {t.Fatal(err)}
+    }
+
+    for i:=0; i<SIZE; i++ {
+        if pval := sh.Pop(); pval != i {t.Fatalf("expected %d, got %d", i, pval)}
+        if err := sh.Validate(); err != nil {t.Fatalf("after popping %d: %v", i, err)}
+    }
+}
+
+
+/*
+Every operation mixed, validating the structure and the invariants after each one.
+ */
+func Test_strictfib_random(t *testing.T) {
+    rng := rand.New(rand.NewSource(1))
+    sh := NewStrictFibonacciHeap()
+    ref := NewPairingHeap()
+    var handles []Handle
+    var refhandles []Handle
+
+    for i:=0; i<5000; i++ {
+        switch op := rng.Intn(20); {
+        case op < 8 || sh.Size() == 0:
+            value := rng.Intn(100000)
+            handles = append(handles, sh.Push(value))
+            refhandles = append(refhandles, ref.Push(value))
+        case op < 12:
+            if sval, rval := sh.Pop(), ref.Pop(); sval != rval {t.Fatalf("op %d: expected %d, got %d", i, rval, sval)}
+        case op < 18:
+            j := rng.Intn(len(handles))
+            if handles[j].(*sfItem).gone {
+                continue
+            }
+            value := handles[j].Value() - rng.Intn(1000)
+            sh.DecreaseKey(handles[j], value)
+            ref.DecreaseKey(refhandles[j], value)
+        case op < 19:
+            j := rng.Intn(len(handles))
+            if handles[j].(*sfItem).gone {
+                continue
+            }
+            sh.Delete(handles[j])
+            ref.Delete(refhandles[j])
+        default:
+            other := NewStrictFibonacciHeap()
+            for k := rng.Intn(50); k > 0; k-- {
+                value := rng.Intn(100000)
+                handles = append(handles, other.Push(value))
+                refhandles = append(refhandles, ref.Push(value))
+            }
+            sh.Merge(other)
+        }
+
+        if sh.Size() != ref.Size() {t.Fatalf("op %d: expected size %d, got %d", i, ref.Size(), sh.Size())}
+        if sh.Size() > 0 && sh.Peek() != ref.Peek() {t.Fatalf("op %d: expected peek %d, got %d", i, ref.Peek(), sh.Peek())}
+        if err := sh.Validate(); err != nil {t.Fatalf("op %d: %v", i, err)}
+    }
+}
+
+
+func Test_strictfib_merge_self(t *testing.T) {
+    sh := NewStrictFibonacciHeap()
+    insert_mult_pq(sh, interval(0, 10))
+
+    mustPanic(t, func() { sh.Merge(sh) })
+    if sh.Size() != 10 {t.Errorf("expected size 10, got %d", sh.Size())}
+    if err := sh.Validate(); err != nil {t.Fatal(err)}
+    for i:=0; i<10; i++ {
+        if pval := sh.Pop(); pval != i {t.Fatalf("expected %d, got %d", i, pval)}
+    }
+}
+
+
+/*
+Merging a large heap into a small one keeps the active nodes of the large one.
+ */
+func Test_strictfib_merge_sides(t *testing.T) {
+    for _, smallfirst := range []bool{true, false} {
+        small := NewStrictFibonacciHeap()
+        big := NewStrictFibonacciHeap()
+        insert_mult_pq(small, interval(0, 10))
+        insert_mult_pq(big, interval(10, 1000))
+        small.Pop()
+        big.Pop()
+
+        var merged *StrictFibonacciHeap
+        if smallfirst {
+            small.Merge(big)
+            merged = small
+            if big.Size() != 0 {t.Errorf("merged heap not emptied")}
+        } else {
+            big.Merge(small)
+            merged = big
+        }
+        if err := merged.Validate(); err != nil {t.Fatal(err)}
+
+        expected := append(interval(1, 10), interval(11, 1000)...)
+        for _, value := range expected {
+            if pval := merged.Pop(); pval != value {t.Fatalf("expected %d, got %d", value, pval)}
+        }
+    }
+}
@@ -0,0 +1,50 @@
+package BrodalOkasakiHeap
+
+import (
+    "math"
+    "math/bits"
+    "time"
+)
+
+
+/*
+The low bits of the Unix nanoseconds that TimeKey() drops so that the rest fits in an int: none on 64 bits, 32 on 32
+bits.
+ */
+const timeKeyShift = 64 - bits.UintSize
+
+
+var (
+    minKeyTime = time.Unix(0, math.MinInt64)
+    maxKeyTime = time.Unix(0, math.MaxInt64)
+)
+
+
+/*
+Turn a point in time into a key, so that earlier times come first in a min-heap.
+
+Where int is 64 bits, the key is the Unix time in nanoseconds. Those only fit in 64 bits between the years 1678 and
+2262, and wrap around outside of that: the zero time.Time, or a far away "never", would come first or last for no
+reason. Such times are clamped to the smallest and the largest int instead, so they still sort before and after every
+other time, and equal to each other.
+
+Where int is 32 bits, the nanoseconds are divided by 2^32 to fit, so the key only moves every 4.3 seconds or so. Times
+closer than that may get the same key, and a KeyedQueue gives those back in the order they were pushed.
+ */
+func TimeKey(t time.Time) int {
+    return int(scaledTimeKey(t, timeKeyShift))
+}
+
+
+/*
+The Unix nanoseconds of "t", clamped to the range of an int64, without their lowest "shift" bits.
+ */
+func scaledTimeKey(t time.Time, shift int) int64 {
+    switch {
+    case t.Before(minKeyTime):
+        return math.MinInt64 >> shift
+    case t.After(maxKeyTime):
+        return math.MaxInt64 >> shift
+    }
+    return t.UnixNano() >> shift
+}
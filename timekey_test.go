@@ -0,0 +1,52 @@
+package BrodalOkasakiHeap
+
+
+import (
+    "math"
+    "testing"
+    "time"
+)
+
+
+func Test_timekey_order(t *testing.T) {
+    now := time.Unix(1700000000, 5)
+    times := []time.Time{
+        {},
+        now.AddDate(-400, 0, 0),
+        now.Add(-time.Nanosecond),
+        now,
+        now.AddDate(300, 0, 0),
+    }
+
+    for i:=1; i<len(times); i++ {
+        if TimeKey(times[i-1]) > TimeKey(times[i]) {t.Errorf("key of %v comes after the key of %v", times[i-1], times[i])}
+    }
+    if TimeKey(time.Time{}) != math.MinInt || TimeKey(now.AddDate(-400, 0, 0)) != math.MinInt {t.Errorf("times before 1678 not clamped")}
+    if TimeKey(now.AddDate(300, 0, 0)) != math.MaxInt {t.Errorf("times after 2262 not clamped")}
+    if TimeKey(now) != int(now.UnixNano() >> timeKeyShift) {t.Errorf("expected Unix nanoseconds, got %d", TimeKey(now))}
+}
+
+
+/*
+What TimeKey() does where int is 32 bits, whatever the size of int here.
+ */
+func Test_timekey_32_bits(t *testing.T) {
+    now := time.Unix(1700000000, 0)
+    times := []time.Time{
+        {},
+        now.AddDate(-400, 0, 0),
+        now,
+        now.Add(5*time.Second),
+        now.AddDate(200, 0, 0),
+        now.AddDate(300, 0, 0),
+    }
+
+    for i, tm := range times {
+        key := scaledTimeKey(tm, 32)
+        if key < math.MinInt32 || key > math.MaxInt32 {t.Errorf("key %d of %v doesn't fit in 32 bits", key, tm)}
+        if i > 0 && scaledTimeKey(times[i-1], 32) > key {t.Errorf("key of %v comes after the key of %v", times[i-1], tm)}
+    }
+    if scaledTimeKey(time.Time{}, 32) != math.MinInt32 {t.Errorf("times before 1678 not clamped")}
+    if scaledTimeKey(now.AddDate(300, 0, 0), 32) != math.MaxInt32 {t.Errorf("times after 2262 not clamped")}
+    if scaledTimeKey(now, 32) == scaledTimeKey(now.Add(5*time.Second), 32) {t.Errorf("times 5 seconds apart got the same key")}
+}
@@ -0,0 +1,112 @@
+package BrodalOkasakiHeap
+
+import "sort"
+
+
+/*
+Keeps track of the K best elements of a stream, e.g. the K slowest requests in a log. Elements are ranked by an integer
+key taken from them, "MaxFirst" keeps the K largest keys and "MinFirst" the K smallest.
+
+The keys live in a BoundedBOHeap that evicts its worst key when full, the elements themselves sit in buckets by key.
+Among elements of equal key, the ones seen first are kept.
+ */
+type TopK[T any] struct {
+    keys	*BoundedBOHeap
+    key		func(T) int
+    buckets	map[int][]T
+}
+
+
+/*
+Create a new tracker of the "k" best elements, ranking them by "key" in the given order.
+ */
+func NewTopK[T any](k int, order Order, key func(T) int) *TopK[T] {
+    return &TopK[T] {
+        keys: NewBoundedBOHeap(k, order, EvictWorst),
+        key: key,
+        buckets: make(map[int][]T),
+    }
+}
+
+
+/*
+Consider an element. Takes O(logK) time.
+ */
+func (tk *TopK[T]) Add(elem T) {
+    key := tk.key(elem)
+
+    evicted, dropped, _ := tk.keys.Offer(key)
+    if dropped && evicted == key {
+        // Not better than the worst element kept.
+        return
+    }
+
+    tk.buckets[key] = append(tk.buckets[key], elem)
+    if dropped {
+        tk.evict(evicted)
+    }
+}
+
+
+/*
+Drop the latest element of the given key.
+ */
+func (tk *TopK[T]) evict(key int) {
+    bucket := tk.buckets[key]
+    if len(bucket) == 1 {
+        delete(tk.buckets, key)
+        return
+    }
+
+    var zero T
+    bucket[len(bucket)-1] = zero
+    tk.buckets[key] = bucket[:len(bucket)-1]
+}
+
+
+/*
+The amount of elements kept, which is K once that many elements were added.
+ */
+func (tk *TopK[T]) Size() int {
+    return tk.keys.Size()
+}
+
+
+/*
+The elements kept so far, best first. The tracker is left as it is. Takes O(KlogK) time.
+ */
+func (tk *TopK[T]) Snapshot() []T {
+    keys := make([]int, 0, len(tk.buckets))
+    for key := range tk.buckets {
+        keys = append(keys, key)
+    }
+    order := tk.keys.order
+    sort.Slice(keys, func(i int, j int) bool { return order.beforeKey(keys[i], keys[j]) })
+
+    elems := make([]T, 0, tk.Size())
+    for _, key := range keys {
+        elems = append(elems, tk.buckets[key]...)
+    }
+    return elems
+}
+
+
+/*
+Take in the elements of another tracker of the same order, e.g. the partial result of another shard, leaving the other
+one empty. The best K elements of both are kept. Panics, leaving both trackers as they are, if the orders differ.
+ */
+func (tk *TopK[T]) Merge(other *TopK[T]) {
+    if other == tk {
+        panic("TopK: can't merge a tracker with itself")
+    }
+    if other.keys.order != tk.keys.order {
+        panic("TopK: can't merge trackers of different order")
+    }
+
+    for key, bucket := range other.buckets {
+        tk.buckets[key] = append(tk.buckets[key], bucket...)
+    }
+    other.buckets = make(map[int][]T)
+
+    tk.keys.merge(other.keys, tk.evict)
+}
@@ -0,0 +1,116 @@
+package BrodalOkasakiHeap
+
+
+import (
+    "testing"
+    "math/rand"
+    "sort"
+)
+
+
+type logLine struct {
+    latency	int
+    id		int
+}
+
+
+func latencyOf(line logLine) int {
+    return line.latency
+}
+
+
+func Test_topk_largest(t *testing.T) {
+    const (
+        SIZE = 1000
+        K = 10
+    )
+    rand.Seed(1)
+
+    tk := NewTopK(K, MaxFirst, latencyOf)
+    for _, latency := range shuffle(interval(0, SIZE)) {
+        tk.Add(logLine{latency, latency})
+    }
+
+    snapshot := tk.Snapshot()
+    if len(snapshot) != K {t.Fatalf("expected %d elements, got %d", K, len(snapshot))}
+    for i, line := range snapshot {
+        if line.latency != SIZE-1-i {t.Errorf("expected %d at %d, got %d", SIZE-1-i, i, line.latency)}
+    }
+
+    // Snapshot() leaves the tracker alone.
+    if tk.Size() != K || len(tk.Snapshot()) != K {t.Errorf("snapshot changed the tracker")}
+}
+
+
+func Test_topk_ties(t *testing.T) {
+    tk := NewTopK(3, MinFirst, latencyOf)
+    for id, latency := range []int{5, 1, 5, 5, 1, 9} {
+        tk.Add(logLine{latency, id})
+    }
+
+    // Among equal keys the elements seen first are kept.
+    expected := []logLine{{1, 1}, {1, 4}, {5, 0}}
+    snapshot := tk.Snapshot()
+    if len(snapshot) != len(expected) {t.Fatalf("expected %v, got %v", expected, snapshot)}
+    for i := range expected {
+        if snapshot[i] != expected[i] {t.Errorf("expected %v, got %v", expected, snapshot)}
+    }
+}
+
+
+func Test_topk_merge(t *testing.T) {
+    const K = 20
+    rng := rand.New(rand.NewSource(1))
+
+    // Every shard tracks its own part of the stream.
+    var all []int
+    shards := make([]*TopK[logLine], 4)
+    for i := range shards {
+        shards[i] = NewTopK(K, MaxFirst, latencyOf)
+        for j:=0; j<500; j++ {
+            latency := rng.Intn(1000)
+            shards[i].Add(logLine{latency, len(all)})
+            all = append(all, latency)
+        }
+    }
+
+    for _, shard := range shards[1:] {
+        shards[0].Merge(shard)
+        if shard.Size() != 0 || len(shard.Snapshot()) != 0 {t.Errorf("merged tracker not emptied")}
+    }
+
+    sort.Sort(sort.Reverse(sort.IntSlice(all)))
+    snapshot := shards[0].Snapshot()
+    if len(snapshot) != K {t.Fatalf("expected %d elements, got %d", K, len(snapshot))}
+    for i, line := range snapshot {
+        if line.latency != all[i] {t.Errorf("expected %d at %d, got %d", all[i], i, line.latency)}
+    }
+}
+
+
+func Test_topk_merge_mismatch(t *testing.T) {
+    tk := NewTopK(3, MaxFirst, latencyOf)
+    other := NewTopK(3, MinFirst, latencyOf)
+    tk.Add(logLine{5, 0})
+    other.Add(logLine{7, 1})
+
+    mustPanic(t, func() { tk.Merge(other) })
+    mustPanic(t, func() { tk.Merge(tk) })
+    if snapshot := tk.Snapshot(); len(snapshot) != 1 || snapshot[0].id != 0 {t.Errorf("failed merge changed the tracker: %v", snapshot)}
+    if snapshot := other.Snapshot(); len(snapshot) != 1 || snapshot[0].id != 1 {t.Errorf("failed merge changed the other tracker: %v", snapshot)}
+}
+
+
+func Test_topk_memory(t *testing.T) {
+    const K = 10
+    tk := NewTopK(K, MaxFirst, latencyOf)
+
+    // An increasing stream evicts on every add, which must not leave anything behind.
+    for i:=0; i<200000; i++ {
+        tk.Add(logLine{i, i})
+
+        dq := tk.keys.keys
+        if dq.min.Size() > 2*K+1 || dq.max.Size() > 2*K+1 {t.Fatalf("add %d: heaps of %d and %d nodes for K=%d", i, dq.min.Size(), dq.max.Size(), K)}
+        if len(tk.buckets) > K {t.Fatalf("add %d: %d buckets for K=%d", i, len(tk.buckets), K)}
+    }
+}
@@ -0,0 +1,63 @@
+package BrodalOkasakiHeap
+
+
+/*
+A Tracer is told about every structural change a heap makes, in the order the heap makes them. Nodes are identified by
+their values, since that is all a user of the heap can see.
+
+Recording the calls between two public operations gives the exact sequence of links behind that Insert(), Pop() or
+Merge(), which is handy both for teaching and for figuring out how a heap ended up in a certain shape.
+ */
+type Tracer interface {
+    OnSkewLink(parent int, child1 int, child2 int, rank int)	// "parent" adopted two trees and now has "rank".
+    OnSimpleLink(parent int, child int, rank int)				// "parent" adopted a tree of same rank and now has "rank".
+    OnSwapRoot(oldroot int, newroot int)						// An inserted node took over the root.
+    OnPromote(value int)										// Pop() moved "value" up to the root.
+    OnSubqueueMerge(root int, size int)							// The subqueue of the root, "size" nodes, was re-inserted.
+}
+
+
+/*
+Install a tracer. Passing nil removes the current one.
+ */
+func (bq *BOHeap) SetTracer(tracer Tracer) {
+    bq.tracer = tracer
+}
+
+
+/*
+skewLink only hands back the new parent, so we work out which two of the three nodes became its children.
+ */
+func (bq *BOHeap) traceSkewLink(parent *BONode, n1 *BONode, n2 *BONode, n3 *BONode) {
+    var children [2]*BONode
+    i := 0
+    for _, node := range [3]*BONode{n1, n2, n3} {
+        if node != parent {
+            children[i] = node
+            i += 1
+        }
+    }
+    bq.tracer.OnSkewLink(parent.value, children[0].value, children[1].value, parent.rank)
+}
+
+
+func (ch *CompactBOHeap) SetTracer(tracer Tracer) {
+    ch.tracer = tracer
+}
+
+
+/*
+Same as "BOHeap.traceSkewLink".
+ */
+func (ch *CompactBOHeap) traceSkewLink(parent int32, n1 int32, n2 int32, n3 int32) {
+    var children [2]int32
+    i := 0
+    for _, n := range [3]int32{n1, n2, n3} {
+        if n != parent {
+            children[i] = n
+            i += 1
+        }
+    }
+    nodes := ch.arena.nodes
+    ch.tracer.OnSkewLink(nodes[parent].value, nodes[children[0]].value, nodes[children[1]].value, int(nodes[parent].rank))
+}
@@ -0,0 +1,106 @@
+package BrodalOkasakiHeap
+
+
+import (
+    "fmt"
+    "testing"
+)
+
+
+// Records every event as a line of text.
+type logTracer struct {
+    events []string
+}
+
+func (lt *logTracer) OnSkewLink(parent int, child1 int, child2 int, rank int) {
+    lt.events = append(lt.events, fmt.Sprintf("skew %d <- %d %d (%d)", parent, child1, child2, rank))
+}
+
+func (lt *logTracer) OnSimpleLink(parent int, child int, rank int) {
+    lt.events = append(lt.events, fmt.Sprintf("simple %d <- %d (%d)", parent, child, rank))
+}
+
+func (lt *logTracer) OnSwapRoot(oldroot int, newroot int) {
+    lt.events = append(lt.events, fmt.Sprintf("swap %d -> %d", oldroot, newroot))
+}
+
+func (lt *logTracer) OnPromote(value int) {
+    lt.events = append(lt.events, fmt.Sprintf("promote %d", value))
+}
+
+func (lt *logTracer) OnSubqueueMerge(root int, size int) {
+    lt.events = append(lt.events, fmt.Sprintf("subqueue %d (%d)", root, size))
+}
+
+
+func (lt *logTracer) expect(t *testing.T, expected ...string) {
+    if len(lt.events) != len(expected) {
+        t.Fatalf("expected events %q, got %q", expected, lt.events)
+    }
+    for i := range expected {
+        if lt.events[i] != expected[i] {t.Errorf("event %d: expected %q, got %q", i, expected[i], lt.events[i])}
+    }
+    lt.events = nil
+}
+
+
+func Test_trace_insert(t *testing.T) {
+    tracer := &logTracer{}
+    heap := NewBOHeap()
+    heap.SetTracer(tracer)
+
+    insert_mult(heap, []int{1, 3, 2})
+    tracer.expect(t)
+
+    heap.Insert(4)
+    tracer.expect(t, "skew 2 <- 3 4 (1)")
+
+    heap.Insert(0)
+    tracer.expect(t, "swap 1 -> 0")
+}
+
+
+func Test_trace_pop(t *testing.T) {
+    tracer := &logTracer{}
+    heap := NewBOHeap()
+    insert_mult(heap, []int{1, 2, 3, 4})
+
+    heap.SetTracer(tracer)
+    heap.Pop()
+    // 2 is removed from under the root, its children 3 and 4 are re-inserted as singletons without any linking.
+    tracer.expect(t, "promote 2")
+
+    insert_mult(heap, []int{5, 6, 7, 8, 9})
+    tracer.events = nil
+    heap.Pop()
+    // 3 leaves the children of the root, re-inserting its subtrees links them, and 3 is moved up to the root.
+    tracer.expect(t, "skew 4 <- 5 9 (1)", "simple 4 <- 6 (2)", "promote 3")
+}
+
+
+func Test_trace_merge(t *testing.T) {
+    tracer := &logTracer{}
+    h1 := NewBOHeap()
+    h2 := NewBOHeap()
+    insert_mult(h1, []int{10, 11})
+    insert_mult(h2, []int{0, 1, 2})
+
+    h1.SetTracer(tracer)
+    h1.Merge(h2)
+    tracer.expect(t, "swap 10 -> 0")
+
+    h1.Pop()
+    if len(tracer.events) == 0 || tracer.events[0] != "subqueue 0 (2)" {t.Errorf("expected subqueue merge first, got %q", tracer.events)}
+}
+
+
+func Test_trace_removed(t *testing.T) {
+    tracer := &logTracer{}
+    heap := NewBOHeap()
+    heap.SetTracer(tracer)
+    heap.SetTracer(nil)
+
+    insert_mult(heap, interval(0, 20))
+    heap.Pop()
+    tracer.expect(t)
+}
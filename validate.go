@@ -0,0 +1,89 @@
+package BrodalOkasakiHeap
+
+import "fmt"
+
+
+/*
+Walk the whole heap and check that it is in a sane state:
+    * No node comes before its parent in the order of the heap, and no subqueued node comes before the node holding it.
+    * Parent and sibling links agree with each other in both directions.
+    * Children lists are rank ordered.
+    * The amount of nodes matches Size().
+
+Returns nil for a sane heap, otherwise the first problem found. It takes O(n) time, so it is meant for tests and for
+digging into a heap that misbehaves.
+ */
+func (bq *BOHeap) Validate() error {
+    if bq.root == nil {
+        if bq.size != 0 {
+            return fmt.Errorf("empty heap with size %d", bq.size)
+        }
+        return nil
+    }
+
+    root := bq.root
+    if root.parent != nil || root.leftsibling != nil || root.rightsibling != nil {
+        return fmt.Errorf("root %d is linked to other nodes", root.value)
+    }
+    if root.index != &bq.index {
+        return fmt.Errorf("root %d does not own the rank index", root.value)
+    }
+    if err := bq.index.validate(root); err != nil {
+        return err
+    }
+
+    count := 0
+    if err := root.validate(bq.order, &count, bq.size); err != nil {
+        return err
+    }
+    if count != bq.size {
+        return fmt.Errorf("heap holds %d nodes but has size %d", count, bq.size)
+    }
+    return nil
+}
+
+
+/*
+Check a node and everything below it, counting the nodes as we go. "limit" keeps us from looping forever in case the
+links form a cycle.
+ */
+func (bon *BONode) validate(order Order, count *int, limit int) error {
+    *count += 1
+    if *count > limit {
+        return fmt.Errorf("more than %d nodes reachable", limit)
+    }
+    if *count > 1 && bon.index != nil {
+        return fmt.Errorf("node %d holds a rank index but is not the root", bon.value)
+    }
+
+    if err := bon.validateList(order, bon.children_head, true, count, limit); err != nil {
+        return err
+    }
+    return bon.validateList(order, bon.subqueue_head, false, count, limit)
+}
+
+
+func (bon *BONode) validateList(order Order, head *BONode, ranked bool, count *int, limit int) error {
+    var prev *BONode
+
+    for node := head; node != nil; node = node.rightsibling {
+        if node.parent != bon {
+            return fmt.Errorf("node %d does not point back to its parent %d", node.value, bon.value)
+        }
+        if node.leftsibling != prev {
+            return fmt.Errorf("node %d has a broken left sibling link", node.value)
+        }
+        if order.before(node, bon) {
+            return fmt.Errorf("node %d comes before its parent %d", node.value, bon.value)
+        }
+        if ranked && prev != nil && prev.rank > node.rank {
+            return fmt.Errorf("children of %d are not rank ordered", bon.value)
+        }
+
+        if err := node.validate(order, count, limit); err != nil {
+            return err
+        }
+        prev = node
+    }
+    return nil
+}